@@ -2,7 +2,12 @@ package app
 
 import (
 	"context"
+	"errors"
 	"net/http"
+	"os"
+	"os/signal"
+	"syscall"
+	"time"
 
 	"github.com/danielgtaylor/huma/v2"
 	"github.com/go-chi/chi/v5"
@@ -14,6 +19,9 @@ import (
 	"github.com/uptrace/bun"
 )
 
+// httpShutdownTimeout bounds how long in-flight HTTP requests may take once the server shuts down
+const httpShutdownTimeout = 10 * time.Second
+
 // App is the main application struct
 type App struct {
 	*utils.WithLogger
@@ -35,10 +43,12 @@ func newApp(r *chi.Mux, h *huma.API, config *utils.DefaultConfig, c *setup.Contr
 	}
 }
 
-// Run starts the application server
+// Run starts the application server and shuts it down gracefully on SIGINT or SIGTERM
 func (a *App) Run() error {
-	// Start the scheduler
-	ctx := context.Background()
+	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt, syscall.SIGTERM)
+	defer stop()
+
+	// Start the scheduler, it stops dispatching once a signal arrives
 	if err := a.controllers.Scheduler.Scheduler(ctx); err != nil {
 		log.Fatal().Err(err).Msg("Failed to start scheduler")
 	}
@@ -47,8 +57,48 @@ func (a *App) Run() error {
 	a.registerRoutes()
 
 	// Start the HTTP server
-	log.Info().Msgf("Starting server on %s", a.config.HTTPAddress)
-	return http.ListenAndServe(a.config.HTTPAddress, a.router)
+	server := &http.Server{Addr: a.config.HTTPAddress, Handler: a.router}
+	serverErr := make(chan error, 1)
+	go func() {
+		log.Info().Msgf("Starting server on %s", a.config.HTTPAddress)
+		serverErr <- server.ListenAndServe()
+	}()
+
+	select {
+	case err := <-serverErr:
+		return err
+	case <-ctx.Done():
+	}
+
+	// A second signal kills the process right away
+	stop()
+
+	return a.shutdown(server)
+}
+
+// shutdown stops the HTTP server, then drains the scheduler's running executions and closes the database.
+// No run is requested once the scheduler drains, runs queued before stay QUEUED for the next leader.
+func (a *App) shutdown(server *http.Server) error {
+	log.Info().Msg("Shutting down")
+
+	ctx, cancel := context.WithTimeout(context.Background(), httpShutdownTimeout)
+	defer cancel()
+
+	if err := server.Shutdown(ctx); err != nil && !errors.Is(err, http.ErrServerClosed) {
+		log.Error().Err(err).Msg("Failed to shut down HTTP server")
+	}
+
+	a.controllers.Scheduler.Shutdown(context.Background())
+
+	// Nil with in-memory storage
+	if a.schedulerDB != nil {
+		if err := a.schedulerDB.Close(); err != nil {
+			return err
+		}
+	}
+
+	log.Info().Msg("Shutdown complete")
+	return nil
 }
 
 // registerRoutes configures all API endpoints
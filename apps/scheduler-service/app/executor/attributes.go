@@ -0,0 +1,34 @@
+package executor
+
+import (
+	"encoding/json"
+	"fmt"
+)
+
+// decodeConfig reads the executor configuration stored under key in the job attributes into config.
+func decodeConfig(attributes map[string]interface{}, key string, config any) error {
+	raw, ok := attributes[key]
+	if !ok {
+		return fmt.Errorf("attribute %q with the %s configuration is missing", key, key)
+	}
+
+	encoded, err := json.Marshal(raw)
+	if err != nil {
+		return fmt.Errorf("invalid %s configuration: %w", key, err)
+	}
+
+	if err := json.Unmarshal(encoded, config); err != nil {
+		return fmt.Errorf("invalid %s configuration: %w", key, err)
+	}
+
+	return nil
+}
+
+// truncate cuts data down to limit bytes and reports whether anything was cut off.
+func truncate(data []byte, limit int) (string, bool) {
+	if len(data) <= limit {
+		return string(data), false
+	}
+
+	return string(data[:limit]), true
+}
@@ -0,0 +1,180 @@
+package executor
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"time"
+)
+
+// TypeCommand runs a local command, configured through the "command" attribute of the job:
+//
+//	{
+//	  "command": {
+//	    "argv": ["/opt/scripts/cleanup.sh", "--days", "30"],
+//	    "dir": "/opt/scripts",
+//	    "env": {"LOG_LEVEL": "debug"},
+//	    "timeout_seconds": 600,
+//	    "max_output_bytes": 65536
+//	  }
+//	}
+//
+// The command only inherits PATH from the scheduler, anything else has to be passed through env.
+// JOB_ID, JOB_RUN_ID and JOB_LOGICAL_TIME (RFC3339, the occurrence the run stands for) are always set.
+// A non-zero exit code fails the run. On timeout or cancellation the whole process group is killed.
+const TypeCommand = "command"
+
+const (
+	defaultCommandTimeout     = 10 * time.Minute
+	defaultMaxCommandOutput   = 64 * 1024
+	commandWaitDelay          = 5 * time.Second
+	commandConfigAttributeKey = TypeCommand
+)
+
+type commandConfig struct {
+	Argv           []string          `json:"argv"`
+	Dir            string            `json:"dir"`
+	Env            map[string]string `json:"env"`
+	TimeoutSeconds int               `json:"timeout_seconds"`
+	MaxOutputBytes int               `json:"max_output_bytes"`
+}
+
+// CommandExecutor runs the configured command and records its exit code and output.
+type CommandExecutor struct{}
+
+func (e *CommandExecutor) Validate(attributes map[string]interface{}) error {
+	_, err := e.parseConfig(attributes)
+	return err
+}
+
+func (e *CommandExecutor) Execute(ctx context.Context, req *Request) (Result, error) {
+	config, err := e.parseConfig(req.Attributes)
+	if err != nil {
+		return nil, err
+	}
+
+	ctx, cancel := context.WithTimeout(ctx, config.timeout())
+	defer cancel()
+
+	stdout := newCappedBuffer(config.maxOutputBytes())
+	stderr := newCappedBuffer(config.maxOutputBytes())
+
+	cmd := exec.CommandContext(ctx, config.Argv[0], config.Argv[1:]...)
+	cmd.Dir = config.Dir
+	cmd.Env = config.environ(req)
+	cmd.Stdout = stdout
+	cmd.Stderr = stderr
+	// Children holding on to stdout/stderr must not keep the run alive once the process is gone
+	cmd.WaitDelay = commandWaitDelay
+	killProcessGroupOnCancel(cmd)
+
+	start := time.Now()
+	runErr := cmd.Run()
+
+	result := Result{
+		"exit_code":        cmd.ProcessState.ExitCode(),
+		"stdout":           stdout.String(),
+		"stdout_truncated": stdout.truncated,
+		"stderr":           stderr.String(),
+		"stderr_truncated": stderr.truncated,
+		"duration_ms":      time.Since(start).Milliseconds(),
+	}
+
+	if ctx.Err() != nil {
+		return result, fmt.Errorf("command %s was stopped: %w", config.Argv[0], ctx.Err())
+	}
+
+	var exitErr *exec.ExitError
+	if errors.As(runErr, &exitErr) {
+		return result, fmt.Errorf("command %s exited with code %d", config.Argv[0], exitErr.ExitCode())
+	}
+	if runErr != nil {
+		return result, fmt.Errorf("failed to run command %s: %w", config.Argv[0], runErr)
+	}
+
+	return result, nil
+}
+
+// parseConfig reads and checks the command attribute.
+func (e *CommandExecutor) parseConfig(attributes map[string]interface{}) (*commandConfig, error) {
+	var config commandConfig
+	if err := decodeConfig(attributes, commandConfigAttributeKey, &config); err != nil {
+		return nil, err
+	}
+
+	if len(config.Argv) == 0 || config.Argv[0] == "" {
+		return nil, fmt.Errorf("invalid command configuration: argv must contain at least the program")
+	}
+
+	if config.Dir != "" && !filepath.IsAbs(config.Dir) {
+		return nil, fmt.Errorf("invalid command configuration: dir %q must be an absolute path", config.Dir)
+	}
+
+	if config.TimeoutSeconds < 0 || config.MaxOutputBytes < 0 {
+		return nil, fmt.Errorf("invalid command configuration: timeout_seconds and max_output_bytes must not be negative")
+	}
+
+	return &config, nil
+}
+
+func (c *commandConfig) environ(req *Request) []string {
+	env := []string{
+		"PATH=" + os.Getenv("PATH"),
+		"JOB_ID=" + req.JobID.String(),
+		"JOB_RUN_ID=" + req.RunID.String(),
+		"JOB_LOGICAL_TIME=" + req.LogicalTime.UTC().Format(time.RFC3339),
+	}
+	for key, value := range c.Env {
+		env = append(env, key+"="+value)
+	}
+
+	return env
+}
+
+func (c *commandConfig) timeout() time.Duration {
+	if c.TimeoutSeconds == 0 {
+		return defaultCommandTimeout
+	}
+
+	return time.Duration(c.TimeoutSeconds) * time.Second
+}
+
+func (c *commandConfig) maxOutputBytes() int {
+	if c.MaxOutputBytes == 0 {
+		return defaultMaxCommandOutput
+	}
+
+	return c.MaxOutputBytes
+}
+
+// cappedBuffer keeps the first limit bytes written to it and drops the rest.
+// It never fails a write, so a chatty process is not killed by a broken pipe.
+type cappedBuffer struct {
+	data      []byte
+	limit     int
+	truncated bool
+}
+
+func newCappedBuffer(limit int) *cappedBuffer {
+	return &cappedBuffer{limit: limit}
+}
+
+func (b *cappedBuffer) Write(p []byte) (int, error) {
+	written := len(p)
+
+	room := b.limit - len(b.data)
+	if len(p) > room {
+		b.truncated = true
+		p = p[:max(room, 0)]
+	}
+	b.data = append(b.data, p...)
+
+	return written, nil
+}
+
+func (b *cappedBuffer) String() string {
+	return string(b.data)
+}
@@ -0,0 +1,8 @@
+//go:build !unix
+
+package executor
+
+import "os/exec"
+
+// killProcessGroupOnCancel falls back to killing only the command itself where process groups are not available.
+func killProcessGroupOnCancel(_ *exec.Cmd) {}
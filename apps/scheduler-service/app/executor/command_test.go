@@ -0,0 +1,79 @@
+//go:build unix
+
+package executor
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+)
+
+func commandAttributes(config map[string]interface{}) map[string]interface{} {
+	return map[string]interface{}{TypeCommand: config}
+}
+
+func TestCommandExecutor_Execute(t *testing.T) {
+	dir := t.TempDir()
+
+	result, err := (&CommandExecutor{}).Execute(context.Background(), &Request{
+		Attributes: commandAttributes(map[string]interface{}{
+			"argv": []string{"sh", "-c", `echo "$GREETING from $(pwd)"; echo oops >&2`},
+			"dir":  dir,
+			"env":  map[string]string{"GREETING": "hello"},
+		}),
+	})
+	require.NoError(t, err)
+
+	resolved, err := filepath.EvalSymlinks(dir)
+	require.NoError(t, err)
+	require.Equal(t, 0, result["exit_code"])
+	require.Contains(t, []string{"hello from " + dir + "\n", "hello from " + resolved + "\n"}, result["stdout"])
+	require.Equal(t, "oops\n", result["stderr"])
+}
+
+func TestCommandExecutor_NonZeroExit(t *testing.T) {
+	result, err := (&CommandExecutor{}).Execute(context.Background(), &Request{
+		Attributes: commandAttributes(map[string]interface{}{
+			"argv":             []string{"sh", "-c", "printf '%0100d' 0; exit 3"},
+			"max_output_bytes": 10,
+		}),
+	})
+	require.ErrorContains(t, err, "exited with code 3")
+	require.Equal(t, 3, result["exit_code"])
+	require.Equal(t, strings.Repeat("0", 10), result["stdout"])
+	require.Equal(t, true, result["stdout_truncated"])
+}
+
+func TestCommandExecutor_KillsProcessGroup(t *testing.T) {
+	marker := filepath.Join(t.TempDir(), "marker")
+
+	// The background child would create the marker file if it outlived the cancelled run
+	ctx, cancel := context.WithTimeout(context.Background(), 500*time.Millisecond)
+	defer cancel()
+
+	start := time.Now()
+	_, err := (&CommandExecutor{}).Execute(ctx, &Request{
+		Attributes: commandAttributes(map[string]interface{}{
+			"argv": []string{"sh", "-c", "(sleep 2; touch " + marker + ") & sleep 30"},
+		}),
+	})
+	require.ErrorContains(t, err, "was stopped")
+	require.Less(t, time.Since(start), 5*time.Second)
+
+	time.Sleep(3 * time.Second)
+	_, statErr := os.Stat(marker)
+	require.True(t, os.IsNotExist(statErr), "child process outlived the cancelled run")
+}
+
+func TestCommandExecutor_Validate(t *testing.T) {
+	executor := &CommandExecutor{}
+
+	require.NoError(t, executor.Validate(commandAttributes(map[string]interface{}{"argv": []string{"true"}})))
+	require.Error(t, executor.Validate(commandAttributes(map[string]interface{}{"argv": []string{}})))
+	require.Error(t, executor.Validate(commandAttributes(map[string]interface{}{"argv": []string{"true"}, "dir": "relative"})))
+}
@@ -0,0 +1,97 @@
+package executor
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/sdivyansh59/digantara-backend-golang-assignment/internal-lib/snowflake"
+)
+
+// Request describes a single run handed to an executor
+type Request struct {
+	JobID   snowflake.ID
+	RunID   snowflake.ID
+	Attempt int
+	// LogicalTime is the occurrence the run stands for: the scheduled time of scheduled and backfill runs,
+	// the time an ad-hoc run was triggered
+	LogicalTime time.Time
+	Attributes  map[string]interface{} // the job's attributes, holds the executor specific configuration
+}
+
+// Result is recorded in the run history once the executor finished
+type Result map[string]interface{}
+
+// Executor runs the actual work of a job.
+// Implementations must stop once ctx is cancelled and return an error if the work failed.
+// ctx carries the deadline of the job's timeout_seconds, if any.
+type Executor interface {
+	Execute(ctx context.Context, req *Request) (Result, error)
+}
+
+// Validator is implemented by executors that can check their configuration before a job is stored
+type Validator interface {
+	Validate(attributes map[string]interface{}) error
+}
+
+// Registry maps the job "type" field to the executor running it
+type Registry struct {
+	mutex     sync.RWMutex
+	executors map[string]Executor
+}
+
+func NewRegistry() *Registry {
+	return &Registry{executors: make(map[string]Executor)}
+}
+
+// Register adds an executor for the given job type, replacing any previous one.
+func (r *Registry) Register(jobType string, executor Executor) {
+	r.mutex.Lock()
+	defer r.mutex.Unlock()
+
+	r.executors[jobType] = executor
+}
+
+// Get returns the executor registered for the job type.
+func (r *Registry) Get(jobType string) (Executor, error) {
+	r.mutex.RLock()
+	executor, ok := r.executors[jobType]
+	r.mutex.RUnlock()
+
+	if !ok {
+		return nil, fmt.Errorf("no executor registered for job type %q, valid types are %s", jobType, strings.Join(r.Types(), ", "))
+	}
+
+	return executor, nil
+}
+
+// Validate checks that an executor is registered for the job type and accepts the job's attributes.
+func (r *Registry) Validate(jobType string, attributes map[string]interface{}) error {
+	executor, err := r.Get(jobType)
+	if err != nil {
+		return err
+	}
+
+	if validator, ok := executor.(Validator); ok {
+		return validator.Validate(attributes)
+	}
+
+	return nil
+}
+
+// Types returns the registered job types in alphabetical order.
+func (r *Registry) Types() []string {
+	r.mutex.RLock()
+	defer r.mutex.RUnlock()
+
+	types := make([]string, 0, len(r.executors))
+	for jobType := range r.executors {
+		types = append(types, jobType)
+	}
+	sort.Strings(types)
+
+	return types
+}
@@ -0,0 +1,19 @@
+package executor
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestRegistry_Validate(t *testing.T) {
+	registry := ProvideRegistry()
+
+	require.NoError(t, registry.Validate(TypeNoop, nil))
+	require.Error(t, registry.Validate(TypeHTTP, nil))
+
+	// Unknown types list the valid ones
+	err := registry.Validate("ftp", nil)
+	require.ErrorContains(t, err, `job type "ftp"`)
+	require.ErrorContains(t, err, "valid types are command, http, noop")
+}
@@ -0,0 +1,206 @@
+package executor
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"slices"
+	"strings"
+	"text/template"
+	"time"
+)
+
+// TypeHTTP calls an HTTP endpoint, configured through the "http" attribute of the job:
+//
+//	{
+//	  "http": {
+//	    "method": "POST",
+//	    "url": "http://billing.internal/invoices/run",
+//	    "headers": {"Authorization": "Bearer xxx"},
+//	    "body": "{\"job\":\"{{.JobID}}\",\"run\":\"{{.RunID}}\"}",
+//	    "expected_status": [200, 202],
+//	    "timeout_seconds": 30
+//	  }
+//	}
+//
+// A string body is a text/template rendered with the Request, any other JSON value is sent as is.
+// For example {{.LogicalTime.Unix}} is the occurrence the run stands for, which lets backfill runs pick their day.
+const TypeHTTP = "http"
+
+const (
+	defaultHTTPTimeout     = 30 * time.Second
+	maxRecordedHTTPBody    = 4 * 1024
+	maxReadHTTPBody        = 1024 * 1024
+	httpConfigAttributeKey = TypeHTTP
+)
+
+type httpConfig struct {
+	Method         string            `json:"method"`
+	URL            string            `json:"url"`
+	Headers        map[string]string `json:"headers"`
+	Body           json.RawMessage   `json:"body"`
+	ExpectedStatus []int             `json:"expected_status"`
+	TimeoutSeconds int               `json:"timeout_seconds"`
+}
+
+// HTTPExecutor sends the configured request and fails the run on unexpected status codes.
+type HTTPExecutor struct {
+	client *http.Client
+}
+
+func NewHTTPExecutor(client *http.Client) *HTTPExecutor {
+	if client == nil {
+		client = &http.Client{}
+	}
+
+	return &HTTPExecutor{client: client}
+}
+
+func (e *HTTPExecutor) Validate(attributes map[string]interface{}) error {
+	_, err := e.parseConfig(attributes)
+	return err
+}
+
+func (e *HTTPExecutor) Execute(ctx context.Context, req *Request) (Result, error) {
+	config, err := e.parseConfig(req.Attributes)
+	if err != nil {
+		return nil, err
+	}
+
+	body, err := config.renderBody(req)
+	if err != nil {
+		return nil, err
+	}
+
+	ctx, cancel := context.WithTimeout(ctx, config.timeout())
+	defer cancel()
+
+	httpReq, err := http.NewRequestWithContext(ctx, config.method(), config.URL, body)
+	if err != nil {
+		return nil, fmt.Errorf("failed to build http request: %w", err)
+	}
+	for key, value := range config.Headers {
+		httpReq.Header.Set(key, value)
+	}
+
+	start := time.Now()
+	resp, err := e.client.Do(httpReq)
+	if err != nil {
+		return nil, fmt.Errorf("http request failed: %w", err)
+	}
+	defer resp.Body.Close()
+
+	respBody, err := io.ReadAll(io.LimitReader(resp.Body, maxReadHTTPBody))
+	if err != nil {
+		return nil, fmt.Errorf("failed to read http response: %w", err)
+	}
+
+	recordedBody, truncated := truncate(respBody, maxRecordedHTTPBody)
+	result := Result{
+		"status_code":    resp.StatusCode,
+		"body":           recordedBody,
+		"body_truncated": truncated,
+		"duration_ms":    time.Since(start).Milliseconds(),
+	}
+
+	if !config.isExpected(resp.StatusCode) {
+		return result, fmt.Errorf("unexpected http status %d from %s %s", resp.StatusCode, config.method(), config.URL)
+	}
+
+	return result, nil
+}
+
+// parseConfig reads and checks the http attribute.
+func (e *HTTPExecutor) parseConfig(attributes map[string]interface{}) (*httpConfig, error) {
+	var config httpConfig
+	if err := decodeConfig(attributes, httpConfigAttributeKey, &config); err != nil {
+		return nil, err
+	}
+
+	parsedURL, err := url.Parse(config.URL)
+	if err != nil || (parsedURL.Scheme != "http" && parsedURL.Scheme != "https") || parsedURL.Host == "" {
+		return nil, fmt.Errorf("invalid http configuration: url %q must be an absolute http(s) url", config.URL)
+	}
+
+	if config.TimeoutSeconds < 0 {
+		return nil, fmt.Errorf("invalid http configuration: timeout_seconds must not be negative")
+	}
+
+	for _, status := range config.ExpectedStatus {
+		if status < 100 || status > 599 {
+			return nil, fmt.Errorf("invalid http configuration: expected status %d is not a valid http status", status)
+		}
+	}
+
+	if _, err := config.bodyTemplate(); err != nil {
+		return nil, err
+	}
+
+	return &config, nil
+}
+
+// bodyTemplate returns the template of a string body, nil if the body is missing or a JSON value.
+func (c *httpConfig) bodyTemplate() (*template.Template, error) {
+	var text string
+	if err := json.Unmarshal(c.Body, &text); err != nil {
+		return nil, nil
+	}
+
+	body, err := template.New("body").Option("missingkey=error").Parse(text)
+	if err != nil {
+		return nil, fmt.Errorf("invalid http configuration: body template: %w", err)
+	}
+
+	return body, nil
+}
+
+// renderBody returns the request body, string bodies are rendered as template with the request as data.
+func (c *httpConfig) renderBody(req *Request) (io.Reader, error) {
+	if len(c.Body) == 0 || string(c.Body) == "null" {
+		return nil, nil
+	}
+
+	bodyTemplate, err := c.bodyTemplate()
+	if err != nil {
+		return nil, err
+	}
+	if bodyTemplate == nil {
+		return bytes.NewReader(c.Body), nil
+	}
+
+	var rendered bytes.Buffer
+	if err := bodyTemplate.Execute(&rendered, req); err != nil {
+		return nil, fmt.Errorf("failed to render http body: %w", err)
+	}
+
+	return &rendered, nil
+}
+
+func (c *httpConfig) method() string {
+	if c.Method == "" {
+		return http.MethodPost
+	}
+
+	return strings.ToUpper(c.Method)
+}
+
+func (c *httpConfig) timeout() time.Duration {
+	if c.TimeoutSeconds == 0 {
+		return defaultHTTPTimeout
+	}
+
+	return time.Duration(c.TimeoutSeconds) * time.Second
+}
+
+// isExpected checks the status against expected_status, any 2xx status is expected by default.
+func (c *httpConfig) isExpected(status int) bool {
+	if len(c.ExpectedStatus) == 0 {
+		return status >= 200 && status < 300
+	}
+
+	return slices.Contains(c.ExpectedStatus, status)
+}
@@ -0,0 +1,116 @@
+package executor
+
+import (
+	"context"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+)
+
+func httpAttributes(config map[string]interface{}) map[string]interface{} {
+	return map[string]interface{}{TypeHTTP: config}
+}
+
+func TestHTTPExecutor_Execute(t *testing.T) {
+	var gotMethod, gotHeader, gotBody string
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		body, _ := io.ReadAll(r.Body)
+		gotMethod, gotHeader, gotBody = r.Method, r.Header.Get("X-Token"), string(body)
+
+		w.WriteHeader(http.StatusAccepted)
+		_, _ = w.Write([]byte(strings.Repeat("a", maxRecordedHTTPBody+10)))
+	}))
+	defer server.Close()
+
+	result, err := NewHTTPExecutor(nil).Execute(context.Background(), &Request{
+		JobID:   1,
+		RunID:   2,
+		Attempt: 3,
+		Attributes: httpAttributes(map[string]interface{}{
+			"method":  "put",
+			"url":     server.URL,
+			"headers": map[string]interface{}{"X-Token": "secret"},
+			"body":    `{"job":"{{.JobID}}","run":"{{.RunID}}","attempt":{{.Attempt}}}`,
+		}),
+	})
+	require.NoError(t, err)
+
+	require.Equal(t, http.MethodPut, gotMethod)
+	require.Equal(t, "secret", gotHeader)
+	require.Equal(t, `{"job":"1","run":"2","attempt":3}`, gotBody)
+
+	require.Equal(t, http.StatusAccepted, result["status_code"])
+	require.Len(t, result["body"], maxRecordedHTTPBody)
+	require.Equal(t, true, result["body_truncated"])
+}
+
+func TestHTTPExecutor_JSONBody(t *testing.T) {
+	var gotBody string
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		body, _ := io.ReadAll(r.Body)
+		gotBody = string(body)
+	}))
+	defer server.Close()
+
+	_, err := NewHTTPExecutor(nil).Execute(context.Background(), &Request{
+		Attributes: httpAttributes(map[string]interface{}{
+			"url":  server.URL,
+			"body": map[string]interface{}{"template": "{{.JobID}}"},
+		}),
+	})
+	require.NoError(t, err)
+	require.Equal(t, `{"template":"{{.JobID}}"}`, gotBody)
+}
+
+func TestHTTPExecutor_UnexpectedStatus(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusOK)
+		_, _ = w.Write([]byte("ok"))
+	}))
+	defer server.Close()
+
+	result, err := NewHTTPExecutor(nil).Execute(context.Background(), &Request{
+		Attributes: httpAttributes(map[string]interface{}{
+			"url":             server.URL,
+			"expected_status": []int{http.StatusCreated},
+		}),
+	})
+	require.ErrorContains(t, err, "unexpected http status 200")
+	require.Equal(t, http.StatusOK, result["status_code"])
+	require.Equal(t, "ok", result["body"])
+}
+
+func TestHTTPExecutor_Timeout(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		select {
+		case <-r.Context().Done():
+		case <-time.After(5 * time.Second):
+		}
+	}))
+	defer server.Close()
+
+	start := time.Now()
+	_, err := NewHTTPExecutor(nil).Execute(context.Background(), &Request{
+		Attributes: httpAttributes(map[string]interface{}{
+			"url":             server.URL,
+			"timeout_seconds": 1,
+		}),
+	})
+	require.Error(t, err)
+	require.Less(t, time.Since(start), 3*time.Second)
+}
+
+func TestHTTPExecutor_Validate(t *testing.T) {
+	executor := NewHTTPExecutor(nil)
+
+	require.NoError(t, executor.Validate(httpAttributes(map[string]interface{}{"url": "https://example.com/hook"})))
+	require.Error(t, executor.Validate(map[string]interface{}{}))
+	require.Error(t, executor.Validate(httpAttributes(map[string]interface{}{"url": "/relative"})))
+	require.Error(t, executor.Validate(httpAttributes(map[string]interface{}{"url": "https://example.com", "body": "{{.Broken"})))
+	require.Error(t, executor.Validate(httpAttributes(map[string]interface{}{"url": "https://example.com", "expected_status": []int{42}})))
+}
@@ -0,0 +1,17 @@
+package executor
+
+import "context"
+
+// TypeNoop is the default job type, its runs do nothing and always succeed.
+const TypeNoop = "noop"
+
+// NoopExecutor is useful for jobs that only exist to be tracked, or to test schedules.
+type NoopExecutor struct{}
+
+func (e *NoopExecutor) Execute(ctx context.Context, _ *Request) (Result, error) {
+	if err := ctx.Err(); err != nil {
+		return nil, err
+	}
+
+	return Result{}, nil
+}
@@ -0,0 +1,11 @@
+package executor
+
+// ProvideRegistry returns the registry with all built-in executors registered
+func ProvideRegistry() *Registry {
+	registry := NewRegistry()
+	registry.Register(TypeNoop, &NoopExecutor{})
+	registry.Register(TypeHTTP, NewHTTPExecutor(nil))
+	registry.Register(TypeCommand, &CommandExecutor{})
+
+	return registry
+}
@@ -3,8 +3,12 @@ package job
 import (
 	"context"
 	"fmt"
+	"slices"
 	"time"
 
+	"github.com/danielgtaylor/huma/v2"
+	"github.com/sdivyansh59/digantara-backend-golang-assignment/app/executor"
+	"github.com/sdivyansh59/digantara-backend-golang-assignment/app/jobrun"
 	"github.com/sdivyansh59/digantara-backend-golang-assignment/app/shared"
 	"github.com/sdivyansh59/digantara-backend-golang-assignment/internal-lib/snowflake"
 	"github.com/sdivyansh59/digantara-backend-golang-assignment/internal-lib/utils"
@@ -12,39 +16,63 @@ import (
 
 type Controller struct {
 	*utils.WithLogger
-	snowflake  *snowflake.Generator
-	converter  *Converter
-	repository IRepository
-	wakeupChan chan *shared.WakeupEvent
+	snowflake     *snowflake.Generator
+	converter     *Converter
+	repository    IRepository
+	runRepository jobrun.IRepository
+	executors     *executor.Registry
+	wakeupChan    chan *shared.WakeupEvent
 }
 
 func NewController(logger *utils.WithLogger, snowflake *snowflake.Generator, converter *Converter,
-	repository IRepository, wakeupChan chan *shared.WakeupEvent) *Controller {
+	repository IRepository, runRepository jobrun.IRepository, executors *executor.Registry,
+	wakeupChan chan *shared.WakeupEvent) *Controller {
 	return &Controller{
-		WithLogger: logger,
-		snowflake:  snowflake,
-		converter:  converter,
-		repository: repository,
-		wakeupChan: wakeupChan,
+		WithLogger:    logger,
+		snowflake:     snowflake,
+		converter:     converter,
+		repository:    repository,
+		runRepository: runRepository,
+		executors:     executors,
+		wakeupChan:    wakeupChan,
 	}
 }
 
 func (c *Controller) FilterJobs(ctx context.Context, input *FilterJobsInput) (*FilterJobsResponse, error) {
 	if !c.isAuthorized(ctx) {
-		return nil, fmt.Errorf("unauthorized: you do not have permission to delete this job")
+		return nil, fmt.Errorf("unauthorized: you do not have permission to list jobs")
+	}
+
+	filter, err := input.filter()
+	if err != nil {
+		return nil, fmt.Errorf("invalid filter: %w", err)
+	}
+
+	if err := input.page(filter); err != nil {
+		return nil, fmt.Errorf("invalid cursor: %w", err)
 	}
 
-	entities, err := c.repository.Filter(ctx)
+	entities, err := c.repository.Filter(ctx, filter)
 	if err != nil {
 		return nil, fmt.Errorf("failed to filter jobs: %w", err)
 	}
 
+	resp := &FilterJobsResponse{}
+
+	// The extra job fetched beyond the page size means there is a next page
+	if len(entities) > input.pageSize() {
+		entities = entities[:input.pageSize()]
+		resp.Body.NextCursor, err = input.nextCursor(&entities[len(entities)-1])
+		if err != nil {
+			return nil, err
+		}
+	}
+
 	jobs := make([]JobDTO, 0, len(entities))
 	for _, entity := range entities {
 		jobs = append(jobs, *c.converter.ToDTO(&entity))
 	}
 
-	resp := &FilterJobsResponse{}
 	resp.Body.Jobs = jobs
 	return resp, nil
 }
@@ -79,32 +107,522 @@ func (c *Controller) CreateJob(ctx context.Context, input *CreateJobInput) (*Cre
 		return nil, fmt.Errorf("unauthorized: you do not have permission to create a job")
 	}
 
+	entity := c.converter.ToEntity(input)
+	err := validateSchedule(entity)
+	if err != nil {
+		return nil, err
+	}
+
+	entity.DependsOn, err = parseDependencies(input.Body.DependsOn, entity.Id)
+	if err != nil {
+		return nil, err
+	}
+
+	err = c.validateDependencies(ctx, entity)
+	if err != nil {
+		return nil, err
+	}
+
+	err = c.executors.Validate(entity.Type, entity.Attributes)
+	if err != nil {
+		return nil, fmt.Errorf("invalid job configuration: %w", err)
+	}
+
+	// Cron jobs without an explicit start run at the next occurrence of their expression
+	if entity.ScheduledAt == 0 && entity.CronExpression != nil {
+		entity.ScheduledAt, _, err = entity.NextScheduledAt(time.Now())
+		if err != nil {
+			return nil, err
+		}
+	}
+
 	// Validate that scheduled time is in the future
 	currentTime := time.Now().Unix()
-	if input.ScheduledAt <= currentTime {
-		return nil, fmt.Errorf("scheduled_at must be a future timestamp (current: %d, provided: %d)", currentTime, input.ScheduledAt)
+	if entity.ScheduledAt <= currentTime {
+		return nil, fmt.Errorf("scheduled_at must be a future timestamp (current: %d, provided: %d)", currentTime, entity.ScheduledAt)
+	}
+	if err := validateSeconds(entity.ScheduledAt); err != nil {
+		return nil, err
 	}
 
-	entity := c.converter.ToEntity(input)
-	err := c.repository.Create(ctx, entity)
+	err = c.repository.Create(ctx, entity)
 	if err != nil {
 		return nil, fmt.Errorf("failed to create job: %w", err)
 	}
 
-	// Notify scheduler of new job (non-blocking)
+	return &CreateJobResponse{
+		Body: *c.converter.ToDTO(entity),
+	}, nil
+}
+
+func (c *Controller) UpdateJob(ctx context.Context, input *UpdateJobInput) (*UpdateJobResponse, error) {
+	if !c.isAuthorized(ctx) {
+		return nil, fmt.Errorf("unauthorized: you do not have permission to update this job")
+	}
+
+	// validate job's id
+	jobID, err := snowflake.ConvertToSnowflake(input.ID)
+	if err != nil {
+		return nil, fmt.Errorf("invalid job ID: %w", err)
+	}
+
+	// Check if job with id exists
+	job, err := c.repository.GetByID(ctx, jobID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to retrieve job: %w", err)
+	}
+	if job == nil {
+		return nil, fmt.Errorf("job not found")
+	}
+
+	// The scheduler writes the job back once the run finishes, which would overwrite this update.
+	// A job paused during its run is still owned by the scheduler until the run finished.
+	if job.Status == shared.JobStatusRunning || job.Owner != nil {
+		return nil, huma.Error409Conflict(fmt.Sprintf("job %s is currently running, try again once the run has finished", job.Id))
+	}
+	readStatus := job.Status
+
+	if input.Body.ScheduledAt != nil {
+		currentTime := time.Now().Unix()
+		if *input.Body.ScheduledAt <= currentTime {
+			return nil, fmt.Errorf("scheduled_at must be a future timestamp (current: %d, provided: %d)", currentTime, *input.Body.ScheduledAt)
+		}
+		if err := validateSeconds(*input.Body.ScheduledAt); err != nil {
+			return nil, err
+		}
+	}
+
+	if input.Body.IntervalTime != nil && input.Body.CronExpression != nil {
+		return nil, fmt.Errorf("interval_time and cron_expression cannot be combined")
+	}
+
+	set := map[string]bool{
+		"description":     input.Body.Description != nil,
+		"interval_time":   input.Body.IntervalTime != nil,
+		"cron_expression": input.Body.CronExpression != nil,
+		"timeout_seconds": input.Body.TimeoutSeconds != nil,
+		"retry_policy":    input.Body.RetryPolicy != nil,
+	}
+	for _, field := range input.Body.Clear {
+		if set[field] {
+			return nil, fmt.Errorf("%s cannot be set and cleared at once", field)
+		}
+	}
+
+	previousScheduledAt := job.ScheduledAt
+	previousCronExpression := utils.SafeDereference(job.CronExpression, "")
+	previousTimezone := job.Timezone
+	columns := c.converter.ApplyUpdate(job, input)
+
+	err = validateSchedule(job)
+	if err != nil {
+		return nil, err
+	}
+
+	if input.Body.DependsOn != nil {
+		job.DependsOn, err = parseDependencies(input.Body.DependsOn, job.Id)
+		if err != nil {
+			return nil, err
+		}
+		columns = append(columns, "depends_on")
+
+		err = c.validateDependencies(ctx, job)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	err = c.executors.Validate(job.Type, job.Attributes)
+	if err != nil {
+		return nil, fmt.Errorf("invalid job configuration: %w", err)
+	}
+
+	// A new cron expression or timezone without an explicit scheduled_at continues at the next occurrence
+	cronChanged := job.CronExpression != nil &&
+		(*job.CronExpression != previousCronExpression || job.Timezone != previousTimezone)
+	if input.Body.ScheduledAt == nil && cronChanged {
+		job.ScheduledAt, _, err = job.NextScheduledAt(time.Now())
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	rescheduled := job.ScheduledAt != previousScheduledAt
+	if rescheduled && job.Status != shared.JobStatusPaused {
+		// A finished or failed job becomes due again once it gets a new scheduled time
+		job.Status = shared.JobStatusScheduled
+	}
+	if rescheduled {
+		// A new scheduled time starts a new occurrence with a fresh set of attempts
+		job.StartOccurrence(job.ScheduledAt)
+		columns = append(columns, "scheduled_at", "status", "occurrence_at", "attempt")
+	}
+
+	// Only the changed columns are written, and only if the scheduler did not claim the job in the meantime
+	slices.Sort(columns)
+	updated, err := c.repository.UpdateIdle(ctx, job, readStatus, slices.Compact(columns)...)
+	if err != nil {
+		return nil, fmt.Errorf("failed to update job: %w", err)
+	}
+	if !updated {
+		return nil, huma.Error409Conflict(fmt.Sprintf("job %s changed while updating it, try again", job.Id))
+	}
+
+	return &UpdateJobResponse{
+		Body: *c.converter.ToDTO(job),
+	}, nil
+}
+
+func (c *Controller) PauseJob(ctx context.Context, input *PauseJobInput) (*PauseJobResponse, error) {
+	if !c.isAuthorized(ctx) {
+		return nil, fmt.Errorf("unauthorized: you do not have permission to pause this job")
+	}
+
+	// validate job's id
+	jobID, err := snowflake.ConvertToSnowflake(input.ID)
+	if err != nil {
+		return nil, fmt.Errorf("invalid job ID: %w", err)
+	}
+
+	// Check if job with id exists
+	job, err := c.repository.GetByID(ctx, jobID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to retrieve job: %w", err)
+	}
+	if job == nil {
+		return nil, fmt.Errorf("job not found")
+	}
+
+	// Jobs waiting for their next run can be paused, recurring jobs also while they run. The run finishes, then the
+	// job stays paused instead of being scheduled again.
+	if job.Status != shared.JobStatusScheduled && (job.Status != shared.JobStatusRunning || !job.IsRecurring()) {
+		return nil, fmt.Errorf("job %s cannot be paused in status %s", job.Id, job.Status)
+	}
+
+	paused, err := c.repository.SwapStatus(ctx, job, shared.JobStatusPaused)
+	if err != nil {
+		return nil, fmt.Errorf("failed to pause job: %w", err)
+	}
+	if !paused {
+		return nil, fmt.Errorf("job %s changed while pausing it, try again", job.Id)
+	}
+
+	return &PauseJobResponse{
+		Body: *c.converter.ToDTO(job),
+	}, nil
+}
+
+func (c *Controller) ResumeJob(ctx context.Context, input *ResumeJobInput) (*ResumeJobResponse, error) {
+	if !c.isAuthorized(ctx) {
+		return nil, fmt.Errorf("unauthorized: you do not have permission to resume this job")
+	}
+
+	// validate job's id
+	jobID, err := snowflake.ConvertToSnowflake(input.ID)
+	if err != nil {
+		return nil, fmt.Errorf("invalid job ID: %w", err)
+	}
+
+	// Check if job with id exists
+	job, err := c.repository.GetByID(ctx, jobID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to retrieve job: %w", err)
+	}
+	if job == nil {
+		return nil, fmt.Errorf("job not found")
+	}
+
+	if job.Status != shared.JobStatusPaused {
+		return nil, fmt.Errorf("job %s is not paused (status: %s)", job.Id, job.Status)
+	}
+
+	if job.Owner != nil {
+		// Paused during its run, which is still going on: the scheduler reschedules the job once it finished
+		resumed, err := c.repository.SwapStatus(ctx, job, shared.JobStatusRunning)
+		if err != nil {
+			return nil, fmt.Errorf("failed to resume job: %w", err)
+		}
+		if !resumed {
+			return nil, fmt.Errorf("job %s changed while resuming it, try again", job.Id)
+		}
+
+		return &ResumeJobResponse{
+			Body: *c.converter.ToDTO(job),
+		}, nil
+	}
+
+	// Skip the runs missed while paused and continue with the next interval
+	nextScheduledAt, err := job.NextRunAfter(time.Now())
+	if err != nil {
+		return nil, fmt.Errorf("failed to compute next run: %w", err)
+	}
+	job.Status = shared.JobStatusScheduled
+	if nextScheduledAt != job.ScheduledAt {
+		job.StartOccurrence(nextScheduledAt)
+	}
+
+	err = c.repository.Update(ctx, job)
+	if err != nil {
+		return nil, fmt.Errorf("failed to resume job: %w", err)
+	}
+
+	return &ResumeJobResponse{
+		Body: *c.converter.ToDTO(job),
+	}, nil
+}
+
+func (c *Controller) RunJob(ctx context.Context, input *RunJobInput) (*RunJobResponse, error) {
+	if !c.isAuthorized(ctx) {
+		return nil, fmt.Errorf("unauthorized: you do not have permission to run this job")
+	}
+
+	// validate job's id
+	jobID, err := snowflake.ConvertToSnowflake(input.ID)
+	if err != nil {
+		return nil, fmt.Errorf("invalid job ID: %w", err)
+	}
+
+	// Check if job with id exists
+	job, err := c.repository.GetByID(ctx, jobID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to retrieve job: %w", err)
+	}
+	if job == nil {
+		return nil, fmt.Errorf("job not found")
+	}
+
+	// Queue the run in the run history, the leader picks it up from there even if this instance stops before
+	run := &jobrun.JobRun{
+		JobId:   job.Id,
+		Status:  shared.RunStatusQueued,
+		Trigger: shared.RunTriggerManual,
+		Attempt: 1,
+	}
+	err = c.runRepository.Create(ctx, run)
+	if err != nil {
+		return nil, fmt.Errorf("failed to queue run: %w", err)
+	}
+
+	// Wake the scheduler of this instance right away. The database notifies the leader of the queued run as well,
+	// so the event may be dropped if the channel is full.
 	select {
-	case c.wakeupChan <- &shared.WakeupEvent{
-		JobID:       entity.Id,
-		ScheduledAt: entity.ScheduledAt,
-	}:
-		c.Logger.Info().Msgf("Notified scheduler of new job %s", entity.Id)
+	case c.wakeupChan <- &shared.WakeupEvent{JobID: job.Id, RunIDs: []snowflake.ID{run.Id}}:
 	default:
-		c.Logger.Warn().Msg("Scheduler wakeup channel is full, skipping notification")
 	}
+	c.Logger.Info().Msgf("Queued ad-hoc run %s for job %s", run.Id, job.Id)
 
-	return &CreateJobResponse{
-		Body: *c.converter.ToDTO(entity),
-	}, nil
+	resp := &RunJobResponse{}
+	resp.Body.RunID = run.Id.String()
+	resp.Body.JobID = job.Id.String()
+	resp.Body.TriggeredAt = run.CreatedAt.Unix()
+	return resp, nil
+}
+
+func (c *Controller) BackfillJob(ctx context.Context, input *BackfillJobInput) (*BackfillJobResponse, error) {
+	if !c.isAuthorized(ctx) {
+		return nil, fmt.Errorf("unauthorized: you do not have permission to backfill this job")
+	}
+
+	// validate job's id
+	jobID, err := snowflake.ConvertToSnowflake(input.ID)
+	if err != nil {
+		return nil, fmt.Errorf("invalid job ID: %w", err)
+	}
+
+	if input.Body.From > input.Body.To {
+		return nil, fmt.Errorf("invalid range: from must not be after to")
+	}
+	if input.Body.To > time.Now().Unix() {
+		return nil, fmt.Errorf("invalid range: to must not be in the future")
+	}
+
+	// Check if job with id exists
+	job, err := c.repository.GetByID(ctx, jobID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to retrieve job: %w", err)
+	}
+	if job == nil {
+		return nil, fmt.Errorf("job not found")
+	}
+	if !job.IsRecurring() {
+		return nil, fmt.Errorf("only recurring jobs can be backfilled")
+	}
+
+	occurrences, err := job.OccurrencesBetween(time.Unix(input.Body.From, 0), time.Unix(input.Body.To, 0), maxBackfillRuns)
+	if err != nil {
+		return nil, fmt.Errorf("invalid range: %w", err)
+	}
+	if len(occurrences) == 0 {
+		return nil, fmt.Errorf("invalid range: the job has no occurrence between %d and %d", input.Body.From, input.Body.To)
+	}
+
+	// Queue the runs in the run history, the leader picks them up from there
+	resp := &BackfillJobResponse{}
+	resp.Body.JobID = job.Id.String()
+	runIDs := make([]snowflake.ID, 0, len(occurrences))
+	for _, occurrence := range occurrences {
+		run := &jobrun.JobRun{
+			JobId:        job.Id,
+			Status:       shared.RunStatusQueued,
+			Trigger:      shared.RunTriggerBackfill,
+			Attempt:      1,
+			ScheduledFor: utils.ToPointer(occurrence),
+		}
+		err = c.runRepository.Create(ctx, run)
+		if err != nil {
+			return nil, fmt.Errorf("failed to queue run for %d: %w", occurrence, err)
+		}
+
+		runIDs = append(runIDs, run.Id)
+		resp.Body.Runs = append(resp.Body.Runs, BackfillRunDTO{RunID: run.Id.String(), ScheduledFor: occurrence})
+	}
+
+	// Like for ad-hoc runs, the event only wakes the scheduler of this instance right away
+	select {
+	case c.wakeupChan <- &shared.WakeupEvent{JobID: job.Id, RunIDs: runIDs}:
+	default:
+	}
+	c.Logger.Info().Msgf("Queued %d backfill run(s) for job %s", len(runIDs), job.Id)
+
+	return resp, nil
+}
+
+func (c *Controller) FilterDeadLetterJobs(ctx context.Context, input *FilterDeadLetterJobsInput) (*FilterDeadLetterJobsResponse, error) {
+	if !c.isAuthorized(ctx) {
+		return nil, fmt.Errorf("unauthorized: you do not have permission to list dead-lettered jobs")
+	}
+
+	filter, err := input.filter()
+	if err != nil {
+		return nil, fmt.Errorf("invalid cursor: %w", err)
+	}
+
+	entities, err := c.repository.Filter(ctx, filter)
+	if err != nil {
+		return nil, fmt.Errorf("failed to filter dead-lettered jobs: %w", err)
+	}
+
+	resp := &FilterDeadLetterJobsResponse{}
+
+	// The extra job fetched beyond the page size means there is a next page
+	if len(entities) > input.pageSize() {
+		entities = entities[:input.pageSize()]
+		resp.Body.NextCursor, err = input.nextCursor(&entities[len(entities)-1])
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	jobIDs := make([]snowflake.ID, 0, len(entities))
+	for _, entity := range entities {
+		jobIDs = append(jobIDs, entity.Id)
+	}
+
+	// Manual runs do not decide the job's status, the last error comes from its schedule
+	lastRuns, err := c.runRepository.GetLatestByJobIDs(ctx, jobIDs, shared.RunTriggerScheduled)
+	if err != nil {
+		return nil, fmt.Errorf("failed to retrieve last runs: %w", err)
+	}
+
+	jobs := make([]DeadLetterJobDTO, 0, len(entities))
+	for _, entity := range entities {
+		jobs = append(jobs, *c.converter.ToDeadLetterDTO(&entity, lastRuns[entity.Id]))
+	}
+
+	resp.Body.Jobs = jobs
+	return resp, nil
+}
+
+func (c *Controller) ReplayDeadLetterJobs(ctx context.Context, input *ReplayDeadLetterJobsInput) (*ReplayDeadLetterJobsResponse, error) {
+	if !c.isAuthorized(ctx) {
+		return nil, fmt.Errorf("unauthorized: you do not have permission to replay jobs")
+	}
+
+	currentTime := time.Now().Unix()
+	scheduledAt := input.Body.ScheduledAt
+	if scheduledAt == 0 {
+		scheduledAt = currentTime
+	}
+	if scheduledAt < currentTime {
+		return nil, fmt.Errorf("scheduled_at must not be in the past (current: %d, provided: %d)", currentTime, scheduledAt)
+	}
+	if err := validateSeconds(scheduledAt); err != nil {
+		return nil, err
+	}
+
+	// Check every job before touching any, so a typo does not leave the replay half done
+	jobs := make([]*Job, 0, len(input.Body.JobIDs))
+	seen := make(map[snowflake.ID]bool, len(input.Body.JobIDs))
+	for _, id := range input.Body.JobIDs {
+		jobID, err := snowflake.ConvertToSnowflake(id)
+		if err != nil {
+			return nil, fmt.Errorf("invalid job ID %q: %w", id, err)
+		}
+		if seen[jobID] {
+			continue
+		}
+		seen[jobID] = true
+
+		job, err := c.repository.GetByID(ctx, jobID)
+		if err != nil {
+			return nil, fmt.Errorf("failed to retrieve job %s: %w", jobID, err)
+		}
+		if job == nil {
+			return nil, fmt.Errorf("job %s not found", jobID)
+		}
+		if job.Status != shared.JobStatusFailed {
+			return nil, fmt.Errorf("job %s is not dead-lettered (status: %s)", job.Id, job.Status)
+		}
+
+		jobs = append(jobs, job)
+	}
+
+	resp := &ReplayDeadLetterJobsResponse{}
+	resp.Body.Jobs = make([]JobDTO, 0, len(jobs))
+
+	for _, job := range jobs {
+		// The replay starts a new occurrence with a fresh set of attempts
+		job.Status = shared.JobStatusScheduled
+		job.StartOccurrence(scheduledAt)
+
+		err := c.repository.Update(ctx, job)
+		if err != nil {
+			return nil, fmt.Errorf("failed to replay job %s: %w", job.Id, err)
+		}
+
+		resp.Body.Jobs = append(resp.Body.Jobs, *c.converter.ToDTO(job))
+	}
+
+	return resp, nil
+}
+
+func (c *Controller) GetDependencyGraph(ctx context.Context, input *GetDependencyGraphInput) (*GetDependencyGraphResponse, error) {
+	if !c.isAuthorized(ctx) {
+		return nil, fmt.Errorf("unauthorized: you do not have permission to view the dependency graph")
+	}
+
+	entities, err := c.repository.GetDependencyGraph(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("failed to retrieve dependency graph: %w", err)
+	}
+
+	if input.JobID != "" {
+		jobID, err := snowflake.ConvertToSnowflake(input.JobID)
+		if err != nil {
+			return nil, fmt.Errorf("invalid job ID: %w", err)
+		}
+
+		component := newDependencyGraph(entities).component(jobID)
+		connected := make([]Job, 0, len(component))
+		for _, entity := range entities {
+			if component[entity.Id] {
+				connected = append(connected, entity)
+			}
+		}
+		entities = connected
+	}
+
+	return c.converter.ToDependencyGraphDTO(entities), nil
 }
 
 func (c *Controller) DeleteJobByID(ctx context.Context, input *DeleteJobByIDInput) (*DeleteJobResponse, error) {
@@ -127,13 +645,81 @@ func (c *Controller) DeleteJobByID(ctx context.Context, input *DeleteJobByIDInpu
 		return nil, fmt.Errorf("job not found")
 	}
 
+	// Downstream jobs would silently lose their dependency
+	dependents, err := c.repository.Filter(ctx, &Filter{DependsOn: job.Id, Limit: 1})
+	if err != nil {
+		return nil, fmt.Errorf("failed to check dependent jobs: %w", err)
+	}
+	if len(dependents) > 0 {
+		return nil, fmt.Errorf("job %s cannot be deleted, job %s depends on it", job.Id, dependents[0].Id)
+	}
+
 	// Proceed to delete the job
 	err = c.repository.DeleteByID(ctx, job)
 	if err != nil {
 		return nil, fmt.Errorf("failed to delete job: %w", err)
 	}
 
+	err = c.runRepository.DeleteByJobID(ctx, job.Id)
+	if err != nil {
+		return nil, fmt.Errorf("failed to delete runs of job: %w", err)
+	}
+
 	resp := &DeleteJobResponse{}
 	resp.Body.Success = true
 	return resp, nil
 }
+
+// validateDependencies checks that the upstream jobs exist and that depending on them does not form a cycle.
+func (c *Controller) validateDependencies(ctx context.Context, job *Job) error {
+	if len(job.DependsOn) == 0 {
+		return nil
+	}
+
+	for _, upstream := range job.DependsOn {
+		_, err := c.repository.GetByID(ctx, upstream)
+		if err != nil {
+			return fmt.Errorf("dependency %s not found: %w", upstream, err)
+		}
+	}
+
+	jobs, err := c.repository.GetDependencyGraph(ctx)
+	if err != nil {
+		return fmt.Errorf("failed to retrieve dependency graph: %w", err)
+	}
+
+	graph := newDependencyGraph(jobs)
+	graph[job.Id] = job.DependsOn
+
+	if cycle := graph.findCycle(); cycle != nil {
+		return fmt.Errorf("dependencies would form a cycle: %s", formatCycle(cycle))
+	}
+
+	return nil
+}
+
+// validateSchedule checks that a job follows at most one recurrence rule, that its cron expression parses,
+// that its timezone is known and that its retry policy is consistent.
+func validateSchedule(job *Job) error {
+	if job.IntervalTime != nil && job.CronExpression != nil {
+		return fmt.Errorf("interval_time and cron_expression cannot be combined")
+	}
+
+	if _, err := LoadTimezone(job.Timezone); err != nil {
+		return err
+	}
+
+	if job.CronExpression != nil {
+		if _, err := ParseCronExpression(*job.CronExpression); err != nil {
+			return err
+		}
+	}
+
+	if job.RetryPolicy != nil {
+		if err := job.RetryPolicy.Validate(); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
@@ -0,0 +1,252 @@
+package job
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"testing"
+	"time"
+
+	"github.com/danielgtaylor/huma/v2"
+	"github.com/sdivyansh59/digantara-backend-golang-assignment/app/executor"
+	"github.com/sdivyansh59/digantara-backend-golang-assignment/app/jobrun"
+	"github.com/sdivyansh59/digantara-backend-golang-assignment/app/shared"
+	"github.com/sdivyansh59/digantara-backend-golang-assignment/internal-lib/snowflake"
+	"github.com/sdivyansh59/digantara-backend-golang-assignment/internal-lib/utils"
+	"github.com/stretchr/testify/require"
+)
+
+// newTestController returns a controller on the in-memory repositories, along with them and its wakeup channel.
+func newTestController(t *testing.T) (*Controller, *MemoryRepository, *jobrun.MemoryRepository, chan *shared.WakeupEvent) {
+	generator, err := snowflake.NewGenerator(1)
+	require.NoError(t, err)
+
+	repository := NewMemoryRepository(generator)
+	runRepository := jobrun.NewMemoryRepository(generator)
+	wakeupChan := make(chan *shared.WakeupEvent, 10)
+	controller := NewController(utils.NewTestWithLogger(), generator, NewConverter(), repository, runRepository,
+		executor.ProvideRegistry(), wakeupChan)
+
+	return controller, repository, runRepository, wakeupChan
+}
+
+func createTestJob(t *testing.T, repository *MemoryRepository, job *Job) *Job {
+	job.Type = executor.TypeNoop
+	job.CreatedBy = "a@b.c"
+	if job.Status == "" {
+		job.Status = shared.JobStatusScheduled
+	}
+	require.NoError(t, repository.Create(context.Background(), job))
+
+	return job
+}
+
+func requireConflict(t *testing.T, err error) {
+	var statusErr huma.StatusError
+	require.ErrorAs(t, err, &statusErr)
+	require.Equal(t, http.StatusConflict, statusErr.GetStatus())
+}
+
+func TestController_UpdateJob_RejectsClaimedJobs(t *testing.T) {
+	ctx := context.Background()
+	controller, repository, _, _ := newTestController(t)
+
+	job := createTestJob(t, repository, &Job{Name: "recurring", IntervalTime: utils.ToPointer(int64(60)), ScheduledAt: time.Now().Unix() - 5})
+	update := &UpdateJobInput{ID: job.Id.String()}
+	update.Body.Name = utils.ToPointer("renamed")
+
+	// RUNNING jobs belong to the scheduler
+	claimed, err := repository.GetNextJobToRun(ctx, time.Minute, "instance-1", time.Minute)
+	require.NoError(t, err)
+	require.Equal(t, job.Id, claimed.Id)
+
+	_, err = controller.UpdateJob(ctx, update)
+	requireConflict(t, err)
+
+	// So do jobs paused during their run, they are still owned until the run finished
+	_, err = controller.PauseJob(ctx, &PauseJobInput{ID: job.Id.String()})
+	require.NoError(t, err)
+
+	_, err = controller.UpdateJob(ctx, update)
+	requireConflict(t, err)
+
+	stored, err := repository.GetByID(ctx, job.Id)
+	require.NoError(t, err)
+	require.Equal(t, "recurring", stored.Name)
+	require.Equal(t, shared.JobStatusPaused, stored.Status)
+	require.Equal(t, "instance-1", *stored.Owner)
+}
+
+func TestController_UpdateJob_Clear(t *testing.T) {
+	ctx := context.Background()
+	controller, repository, _, _ := newTestController(t)
+
+	job := createTestJob(t, repository, &Job{
+		Name:           "recurring",
+		Description:    utils.ToPointer("every hour"),
+		IntervalTime:   utils.ToPointer(int64(60)),
+		TimeoutSeconds: utils.ToPointer(int64(30)),
+		ScheduledAt:    time.Now().Add(time.Hour).Unix(),
+	})
+
+	// Clearing the interval turns the job into a one-time job, other fields are left alone
+	update := &UpdateJobInput{ID: job.Id.String()}
+	update.Body.Clear = []string{"interval_time", "description"}
+	_, err := controller.UpdateJob(ctx, update)
+	require.NoError(t, err)
+
+	stored, err := repository.GetByID(ctx, job.Id)
+	require.NoError(t, err)
+	require.False(t, stored.IsRecurring())
+	require.Nil(t, stored.Description)
+	require.Equal(t, int64(30), *stored.TimeoutSeconds)
+	require.Equal(t, job.ScheduledAt, stored.ScheduledAt)
+
+	// A field cannot be set and cleared at once
+	update = &UpdateJobInput{ID: job.Id.String()}
+	update.Body.TimeoutSeconds = utils.ToPointer(int64(60))
+	update.Body.Clear = []string{"timeout_seconds"}
+	_, err = controller.UpdateJob(ctx, update)
+	require.ErrorContains(t, err, "timeout_seconds cannot be set and cleared at once")
+}
+
+func TestController_RunJob_QueuesManualRun(t *testing.T) {
+	ctx := context.Background()
+	controller, repository, runRepository, wakeupChan := newTestController(t)
+
+	job := createTestJob(t, repository, &Job{Name: "adhoc", ScheduledAt: time.Now().Add(time.Hour).Unix()})
+
+	resp, err := controller.RunJob(ctx, &RunJobInput{ID: job.Id.String()})
+	require.NoError(t, err)
+
+	// The run waits in the run history for the leader, the local scheduler is woken up for it
+	queued, err := runRepository.GetQueued(ctx, 10, nil)
+	require.NoError(t, err)
+	require.Len(t, queued, 1)
+	require.Equal(t, resp.Body.RunID, queued[0].Id.String())
+	require.Equal(t, shared.RunTriggerManual, queued[0].Trigger)
+
+	event := <-wakeupChan
+	require.Equal(t, job.Id, event.JobID)
+	require.Equal(t, []snowflake.ID{queued[0].Id}, event.RunIDs)
+
+	// The schedule of the job is left alone
+	stored, err := repository.GetByID(ctx, job.Id)
+	require.NoError(t, err)
+	require.Equal(t, shared.JobStatusScheduled, stored.Status)
+	require.Equal(t, job.ScheduledAt, stored.ScheduledAt)
+}
+
+func TestController_FilterJobs_CursorRoundTrip(t *testing.T) {
+	ctx := context.Background()
+	controller, repository, _, _ := newTestController(t)
+
+	now := time.Now().Unix()
+	var created []*Job
+	for i, offset := range []int64{300, 100, 500, 100, 200} {
+		created = append(created, createTestJob(t, repository, &Job{Name: fmt.Sprintf("job-%d", i), ScheduledAt: now + offset}))
+	}
+	createTestJob(t, repository, &Job{Name: "paused", Status: shared.JobStatusPaused, ScheduledAt: now})
+
+	// Pages of two follow the sort, ties on scheduled_at are broken by id
+	input := &FilterJobsInput{Status: []string{string(shared.JobStatusScheduled)}, Sort: "-scheduled_at", Limit: 2}
+	var names []string
+	for pages := 0; ; pages++ {
+		require.Less(t, pages, 3)
+
+		resp, err := controller.FilterJobs(ctx, input)
+		require.NoError(t, err)
+		for _, job := range resp.Body.Jobs {
+			names = append(names, job.Name)
+		}
+
+		if resp.Body.NextCursor == "" {
+			break
+		}
+		input.Cursor = resp.Body.NextCursor
+	}
+	require.Equal(t, []string{"job-2", "job-0", "job-4", "job-3", "job-1"}, names)
+	require.Len(t, created, len(names))
+
+	// A cursor only continues the sort it was created for
+	input.Sort = "created_at"
+	_, err := controller.FilterJobs(ctx, input)
+	require.ErrorContains(t, err, "invalid cursor")
+}
+
+func TestController_ReplayDeadLetterJobs_ResetsOccurrence(t *testing.T) {
+	ctx := context.Background()
+	controller, repository, _, _ := newTestController(t)
+
+	failed := createTestJob(t, repository, &Job{
+		Name:         "failed",
+		Status:       shared.JobStatusFailed,
+		ScheduledAt:  time.Now().Unix() - 60,
+		RetryPolicy:  &RetryPolicy{MaxAttempts: 3},
+		Attempt:      3,
+		OccurrenceAt: utils.ToPointer(time.Now().Unix() - 600),
+	})
+	scheduled := createTestJob(t, repository, &Job{Name: "scheduled", ScheduledAt: time.Now().Unix() + 60})
+
+	// A job that is not dead-lettered rejects the whole replay
+	input := &ReplayDeadLetterJobsInput{}
+	input.Body.JobIDs = []string{failed.Id.String(), scheduled.Id.String()}
+	_, err := controller.ReplayDeadLetterJobs(ctx, input)
+	require.ErrorContains(t, err, "is not dead-lettered")
+
+	stored, err := repository.GetByID(ctx, failed.Id)
+	require.NoError(t, err)
+	require.Equal(t, shared.JobStatusFailed, stored.Status)
+
+	// The replay starts a new occurrence at scheduled_at with a fresh set of attempts
+	scheduledAt := time.Now().Unix() + 3600
+	input.Body.JobIDs = []string{failed.Id.String(), failed.Id.String()}
+	input.Body.ScheduledAt = scheduledAt
+	resp, err := controller.ReplayDeadLetterJobs(ctx, input)
+	require.NoError(t, err)
+	require.Len(t, resp.Body.Jobs, 1)
+
+	stored, err = repository.GetByID(ctx, failed.Id)
+	require.NoError(t, err)
+	require.Equal(t, shared.JobStatusScheduled, stored.Status)
+	require.Equal(t, scheduledAt, stored.ScheduledAt)
+	require.Equal(t, 1, stored.Attempt)
+	require.Nil(t, stored.OccurrenceAt)
+
+	// It is no longer dead-lettered
+	_, err = controller.ReplayDeadLetterJobs(ctx, input)
+	require.ErrorContains(t, err, "is not dead-lettered")
+}
+
+func TestController_BackfillJob_BoundsRuns(t *testing.T) {
+	ctx := context.Background()
+	controller, repository, runRepository, _ := newTestController(t)
+
+	job := createTestJob(t, repository, &Job{Name: "hourly", IntervalTime: utils.ToPointer(int64(60)), ScheduledAt: time.Now().Unix()})
+	to := time.Now().Unix()
+
+	// More occurrences than maxBackfillRuns reject the backfill without queueing anything
+	input := &BackfillJobInput{ID: job.Id.String()}
+	input.Body.From = to - (maxBackfillRuns+1)*3600
+	input.Body.To = to
+	_, err := controller.BackfillJob(ctx, input)
+	require.ErrorContains(t, err, "invalid range")
+
+	queued, err := runRepository.GetQueued(ctx, 10, nil)
+	require.NoError(t, err)
+	require.Empty(t, queued)
+
+	// Otherwise one BACKFILL run is queued for each occurrence
+	input.Body.From = to - 3*3600 + 1
+	resp, err := controller.BackfillJob(ctx, input)
+	require.NoError(t, err)
+	require.Len(t, resp.Body.Runs, 3)
+
+	queued, err = runRepository.GetQueued(ctx, 10, nil)
+	require.NoError(t, err)
+	require.Len(t, queued, 3)
+	for i, run := range queued {
+		require.Equal(t, shared.RunTriggerBackfill, run.Trigger)
+		require.Equal(t, resp.Body.Runs[i].ScheduledFor, *run.ScheduledFor)
+	}
+}
@@ -1,6 +1,14 @@
 package job
 
-import "github.com/sdivyansh59/digantara-backend-golang-assignment/app/shared"
+import (
+	"time"
+
+	"github.com/sdivyansh59/digantara-backend-golang-assignment/app/executor"
+	"github.com/sdivyansh59/digantara-backend-golang-assignment/app/jobrun"
+	"github.com/sdivyansh59/digantara-backend-golang-assignment/app/shared"
+	"github.com/sdivyansh59/digantara-backend-golang-assignment/internal-lib/snowflake"
+	"github.com/sdivyansh59/digantara-backend-golang-assignment/internal-lib/utils"
+)
 
 type Converter struct {
 }
@@ -14,18 +22,113 @@ func (c *Converter) ToDTO(entity *Job) *JobDTO {
 		return nil
 	}
 
-	return &JobDTO{
-		ID:          entity.Id.String(),
-		Name:        entity.Name,
-		Description: entity.Description,
-		Status:      entity.Status,
-		ScheduledAt: entity.ScheduledAt,
-		LastRunAt:   entity.LastRunAt,
-		Attributes:  entity.Attributes,
-		CreatedBy:   entity.CreatedBy,
-		CreatedAt:   entity.CreatedAt,
-		UpdatedAt:   entity.UpdatedAt,
+	dto := &JobDTO{
+		ID:                entity.Id.String(),
+		Name:              entity.Name,
+		Description:       entity.Description,
+		Status:            entity.Status,
+		Type:              entity.Type,
+		IntervalTime:      entity.IntervalTime,
+		CronExpression:    entity.CronExpression,
+		Timezone:          entity.Timezone,
+		ScheduledAt:       entity.ScheduledAt,
+		LastRunAt:         entity.LastRunAt,
+		Attributes:        entity.Attributes,
+		SuccessfulRuns:    entity.SuccessfulRuns,
+		TimeoutSeconds:    entity.TimeoutSeconds,
+		RetryPolicy:       entity.RetryPolicy,
+		Attempt:           entity.Attempt,
+		OccurrenceAt:      entity.OccurrenceAt,
+		LastSucceededAt:   entity.LastSucceededAt,
+		ConcurrencyPolicy: entity.ConcurrencyPolicy,
+		Priority:          entity.Priority,
+		MisfirePolicy:     entity.MisfirePolicy,
+		Owner:             entity.Owner,
+		LeaseExpiresAt:    entity.LeaseExpiresAt,
+		CreatedBy:         entity.CreatedBy,
+		CreatedAt:         entity.CreatedAt,
+		UpdatedAt:         entity.UpdatedAt,
+	}
+
+	if len(entity.DependsOn) > 0 {
+		dto.DependsOn = snowflake.ConvertToStrings(entity.DependsOn)
+	}
+
+	if entity.Status == shared.JobStatusScheduled {
+		nextRunAt := time.Unix(entity.ScheduledAt, 0).In(entity.Location())
+		dto.NextRunAt = utils.ToPointer(entity.ScheduledAt)
+		dto.NextRunAtLocal = utils.ToPointer(nextRunAt.Format(time.RFC3339))
 	}
+
+	return dto
+}
+
+// ToDeadLetterDTO combines a failed job with its last failed scheduled run, which may be nil.
+func (c *Converter) ToDeadLetterDTO(entity *Job, lastRun *jobrun.JobRun) *DeadLetterJobDTO {
+	if entity == nil {
+		return nil
+	}
+
+	dto := &DeadLetterJobDTO{
+		Job:           *c.ToDTO(entity),
+		FailureReason: shared.FailureReasonExecutionFailed,
+		Attempts:      entity.Attempt,
+	}
+
+	// Retries were used up only if the job was actually retried up to the limit, not merely allowed to be
+	if entity.RetryPolicy != nil && entity.Attempt > 1 && entity.Attempt >= entity.RetryPolicy.MaxAttempts {
+		dto.FailureReason = shared.FailureReasonRetriesExhausted
+	}
+
+	if lastRun != nil {
+		dto.LastError = lastRun.ErrorMessage
+		dto.LastRunID = utils.ToPointer(lastRun.Id.String())
+		dto.FailedAt = lastRun.FinishedAt
+
+		switch {
+		case lastRun.Status == shared.RunStatusMisfired:
+			// One-time jobs whose only occurrence was skipped after a misfire never ran at all
+			dto.FailureReason = shared.FailureReasonMisfired
+		case lastRun.Status == shared.RunStatusTimedOut && dto.FailureReason != shared.FailureReasonRetriesExhausted:
+			dto.FailureReason = shared.FailureReasonTimedOut
+		}
+	}
+
+	return dto
+}
+
+// ToDependencyGraphDTO lists the jobs as nodes and their dependencies as edges.
+// Dependencies on jobs missing from the list are left out.
+func (c *Converter) ToDependencyGraphDTO(entities []Job) *GetDependencyGraphResponse {
+	resp := &GetDependencyGraphResponse{}
+	resp.Body.Nodes = make([]DependencyNodeDTO, 0, len(entities))
+	resp.Body.Edges = make([]DependencyEdgeDTO, 0)
+
+	included := make(map[snowflake.ID]bool, len(entities))
+	for _, entity := range entities {
+		included[entity.Id] = true
+	}
+
+	for _, entity := range entities {
+		resp.Body.Nodes = append(resp.Body.Nodes, DependencyNodeDTO{
+			ID:              entity.Id.String(),
+			Name:            entity.Name,
+			Status:          entity.Status,
+			ScheduledAt:     entity.ScheduledAt,
+			LastSucceededAt: entity.LastSucceededAt,
+		})
+
+		for _, upstream := range entity.DependsOn {
+			if included[upstream] {
+				resp.Body.Edges = append(resp.Body.Edges, DependencyEdgeDTO{
+					Upstream:   upstream.String(),
+					Downstream: entity.Id.String(),
+				})
+			}
+		}
+	}
+
+	return resp
 }
 
 func (c *Converter) ToEntity(dto *CreateJobInput) *Job {
@@ -33,12 +136,128 @@ func (c *Converter) ToEntity(dto *CreateJobInput) *Job {
 		return nil
 	}
 
+	timezone := dto.Body.Timezone
+	if timezone == "" {
+		timezone = DefaultTimezone
+	}
+
+	jobType := dto.Body.Type
+	if jobType == "" {
+		jobType = executor.TypeNoop
+	}
+
+	concurrencyPolicy := dto.Body.ConcurrencyPolicy
+	if concurrencyPolicy == "" {
+		concurrencyPolicy = shared.ConcurrencyPolicyAllow
+	}
+
+	misfirePolicy := dto.Body.MisfirePolicy
+	if misfirePolicy == "" {
+		misfirePolicy = shared.MisfirePolicyFireOnceNow
+	}
+
 	return &Job{
-		Name:        dto.Name,
-		Description: dto.Description,
-		Status:      shared.JobStatusScheduled, // default status
-		ScheduledAt: dto.ScheduledAt,
-		Attributes:  dto.Attributes,
-		CreatedBy:   dto.CreatedBy,
+		Name:              dto.Body.Name,
+		Description:       dto.Body.Description,
+		Status:            shared.JobStatusScheduled, // default status
+		Type:              jobType,
+		IntervalTime:      dto.Body.IntervalTime,
+		CronExpression:    dto.Body.CronExpression,
+		Timezone:          timezone,
+		ScheduledAt:       dto.Body.ScheduledAt,
+		Attributes:        dto.Body.Attributes,
+		TimeoutSeconds:    dto.Body.TimeoutSeconds,
+		RetryPolicy:       dto.Body.RetryPolicy,
+		Attempt:           1,
+		ConcurrencyPolicy: concurrencyPolicy,
+		Priority:          dto.Body.Priority,
+		MisfirePolicy:     misfirePolicy,
+		CreatedBy:         dto.Body.CreatedBy,
+	}
+}
+
+// ApplyUpdate copies the fields present in the update body onto the entity and resets the fields listed in clear.
+// Returns the columns it changed.
+func (c *Converter) ApplyUpdate(entity *Job, dto *UpdateJobInput) []string {
+	if entity == nil || dto == nil {
+		return nil
+	}
+
+	var columns []string
+
+	if dto.Body.Name != nil {
+		entity.Name = *dto.Body.Name
+		columns = append(columns, "name")
+	}
+	if dto.Body.Description != nil {
+		entity.Description = dto.Body.Description
+		columns = append(columns, "description")
 	}
+	if dto.Body.Type != nil {
+		entity.Type = *dto.Body.Type
+		columns = append(columns, "type")
+	}
+	// A job follows either an interval or a cron expression, setting one replaces the other
+	if dto.Body.IntervalTime != nil {
+		entity.IntervalTime = dto.Body.IntervalTime
+		entity.CronExpression = nil
+		columns = append(columns, "interval_time", "cron_expression")
+	}
+	if dto.Body.CronExpression != nil {
+		entity.CronExpression = dto.Body.CronExpression
+		entity.IntervalTime = nil
+		columns = append(columns, "cron_expression", "interval_time")
+	}
+	if dto.Body.ScheduledAt != nil {
+		entity.ScheduledAt = *dto.Body.ScheduledAt
+		columns = append(columns, "scheduled_at")
+	}
+	if dto.Body.Timezone != nil {
+		entity.Timezone = *dto.Body.Timezone
+		columns = append(columns, "timezone")
+	}
+	if dto.Body.Attributes != nil {
+		entity.Attributes = dto.Body.Attributes
+		columns = append(columns, "attributes")
+	}
+	if dto.Body.TimeoutSeconds != nil {
+		entity.TimeoutSeconds = dto.Body.TimeoutSeconds
+		columns = append(columns, "timeout_seconds")
+	}
+	if dto.Body.RetryPolicy != nil {
+		entity.RetryPolicy = dto.Body.RetryPolicy
+		columns = append(columns, "retry_policy")
+	}
+	if dto.Body.ConcurrencyPolicy != nil {
+		entity.ConcurrencyPolicy = *dto.Body.ConcurrencyPolicy
+		columns = append(columns, "concurrency_policy")
+	}
+	if dto.Body.Priority != nil {
+		entity.Priority = *dto.Body.Priority
+		columns = append(columns, "priority")
+	}
+	if dto.Body.MisfirePolicy != nil {
+		entity.MisfirePolicy = *dto.Body.MisfirePolicy
+		columns = append(columns, "misfire_policy")
+	}
+
+	for _, field := range dto.Body.Clear {
+		switch field {
+		case "description":
+			entity.Description = nil
+		case "interval_time":
+			entity.IntervalTime = nil
+		case "cron_expression":
+			entity.CronExpression = nil
+		case "timeout_seconds":
+			entity.TimeoutSeconds = nil
+		case "retry_policy":
+			entity.RetryPolicy = nil
+		default:
+			continue
+		}
+		columns = append(columns, field)
+	}
+
+	return columns
 }
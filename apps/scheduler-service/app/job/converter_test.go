@@ -0,0 +1,26 @@
+package job
+
+import (
+	"testing"
+
+	"github.com/sdivyansh59/digantara-backend-golang-assignment/app/jobrun"
+	"github.com/sdivyansh59/digantara-backend-golang-assignment/app/shared"
+	"github.com/stretchr/testify/require"
+)
+
+func TestConverter_ToDeadLetterDTO_FailureReason(t *testing.T) {
+	converter := NewConverter()
+	timedOut := &jobrun.JobRun{Status: shared.RunStatusTimedOut}
+
+	// A retry policy alone does not mean the retries were used up
+	job := &Job{Attempt: 1, RetryPolicy: &RetryPolicy{MaxAttempts: 3}}
+	require.Equal(t, shared.FailureReasonExecutionFailed, converter.ToDeadLetterDTO(job, nil).FailureReason)
+	require.Equal(t, shared.FailureReasonTimedOut, converter.ToDeadLetterDTO(job, timedOut).FailureReason)
+
+	job.Attempt = 3
+	require.Equal(t, shared.FailureReasonRetriesExhausted, converter.ToDeadLetterDTO(job, nil).FailureReason)
+	require.Equal(t, shared.FailureReasonRetriesExhausted, converter.ToDeadLetterDTO(job, timedOut).FailureReason)
+
+	// Without a retry policy the only attempt failed
+	require.Equal(t, shared.FailureReasonExecutionFailed, converter.ToDeadLetterDTO(&Job{Attempt: 1}, nil).FailureReason)
+}
@@ -0,0 +1,132 @@
+package job
+
+import (
+	"fmt"
+	"slices"
+	"strings"
+
+	"github.com/sdivyansh59/digantara-backend-golang-assignment/internal-lib/snowflake"
+)
+
+// maxDependencies bounds the number of upstream jobs of a single job
+const maxDependencies = 50
+
+// dependencyGraph maps each job to the upstream jobs it depends on.
+type dependencyGraph map[snowflake.ID][]snowflake.ID
+
+func newDependencyGraph(jobs []Job) dependencyGraph {
+	graph := make(dependencyGraph, len(jobs))
+	for _, job := range jobs {
+		graph[job.Id] = job.DependsOn
+	}
+
+	return graph
+}
+
+// findCycle returns the jobs forming a dependency cycle, the first job repeated at the end.
+// Returns nil if the graph is acyclic.
+func (g dependencyGraph) findCycle() []snowflake.ID {
+	const (
+		unvisited = iota
+		visiting
+		visited
+	)
+
+	state := make(map[snowflake.ID]int, len(g))
+	var path []snowflake.ID
+
+	var visit func(id snowflake.ID) []snowflake.ID
+	visit = func(id snowflake.ID) []snowflake.ID {
+		state[id] = visiting
+		path = append(path, id)
+
+		for _, upstream := range g[id] {
+			switch state[upstream] {
+			case visiting:
+				start := slices.Index(path, upstream)
+				return append(slices.Clone(path[start:]), upstream)
+			case unvisited:
+				if cycle := visit(upstream); cycle != nil {
+					return cycle
+				}
+			}
+		}
+
+		state[id] = visited
+		path = path[:len(path)-1]
+		return nil
+	}
+
+	// Walk the jobs in a fixed order so the reported cycle does not depend on map iteration
+	ids := make([]snowflake.ID, 0, len(g))
+	for id := range g {
+		ids = append(ids, id)
+	}
+	slices.Sort(ids)
+
+	for _, id := range ids {
+		if state[id] == unvisited {
+			if cycle := visit(id); cycle != nil {
+				return cycle
+			}
+		}
+	}
+
+	return nil
+}
+
+// component returns the jobs connected to id through dependencies in either direction, including id itself.
+func (g dependencyGraph) component(id snowflake.ID) map[snowflake.ID]bool {
+	neighbours := make(map[snowflake.ID][]snowflake.ID)
+	for downstream, upstreams := range g {
+		for _, upstream := range upstreams {
+			neighbours[downstream] = append(neighbours[downstream], upstream)
+			neighbours[upstream] = append(neighbours[upstream], downstream)
+		}
+	}
+
+	component := map[snowflake.ID]bool{id: true}
+	queue := []snowflake.ID{id}
+	for len(queue) > 0 {
+		current := queue[0]
+		queue = queue[1:]
+
+		for _, next := range neighbours[current] {
+			if !component[next] {
+				component[next] = true
+				queue = append(queue, next)
+			}
+		}
+	}
+
+	return component
+}
+
+// parseDependencies converts the upstream job ids of a request, rejecting duplicates and the job itself.
+func parseDependencies(ids []string, self snowflake.ID) ([]snowflake.ID, error) {
+	if len(ids) > maxDependencies {
+		return nil, fmt.Errorf("a job can depend on at most %d jobs", maxDependencies)
+	}
+
+	dependencies := make([]snowflake.ID, 0, len(ids))
+	for _, id := range ids {
+		upstream, err := snowflake.ConvertToSnowflake(id)
+		if err != nil {
+			return nil, fmt.Errorf("invalid dependency ID %q: %w", id, err)
+		}
+		if upstream == self {
+			return nil, fmt.Errorf("job %s cannot depend on itself", self)
+		}
+		if slices.Contains(dependencies, upstream) {
+			return nil, fmt.Errorf("duplicate dependency %s", upstream)
+		}
+
+		dependencies = append(dependencies, upstream)
+	}
+
+	return dependencies, nil
+}
+
+func formatCycle(cycle []snowflake.ID) string {
+	return strings.Join(snowflake.ConvertToStrings(cycle), " -> ")
+}
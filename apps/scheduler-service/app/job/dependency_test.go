@@ -0,0 +1,44 @@
+package job
+
+import (
+	"testing"
+
+	"github.com/sdivyansh59/digantara-backend-golang-assignment/internal-lib/snowflake"
+	"github.com/stretchr/testify/require"
+)
+
+func TestDependencyGraph_FindCycle(t *testing.T) {
+	// ingest <- process <- publish
+	pipeline := dependencyGraph{1: nil, 2: {1}, 3: {2}}
+	require.Nil(t, pipeline.findCycle())
+
+	// diamond: 4 depends on 2 and 3, both depend on 1
+	diamond := dependencyGraph{1: nil, 2: {1}, 3: {1}, 4: {2, 3}}
+	require.Nil(t, diamond.findCycle())
+
+	cyclic := dependencyGraph{1: {3}, 2: {1}, 3: {2}, 4: {1}}
+	require.Equal(t, []snowflake.ID{1, 3, 2, 1}, cyclic.findCycle())
+}
+
+func TestDependencyGraph_Component(t *testing.T) {
+	graph := dependencyGraph{2: {1}, 3: {2}, 5: {4}}
+
+	require.Equal(t, map[snowflake.ID]bool{1: true, 2: true, 3: true}, graph.component(3))
+	require.Equal(t, map[snowflake.ID]bool{4: true, 5: true}, graph.component(4))
+	require.Equal(t, map[snowflake.ID]bool{6: true}, graph.component(6))
+}
+
+func TestParseDependencies(t *testing.T) {
+	dependencies, err := parseDependencies([]string{"1", "2"}, 3)
+	require.NoError(t, err)
+	require.Equal(t, []snowflake.ID{1, 2}, dependencies)
+
+	_, err = parseDependencies([]string{"1", "1"}, 3)
+	require.Error(t, err)
+
+	_, err = parseDependencies([]string{"3"}, 3)
+	require.Error(t, err)
+
+	_, err = parseDependencies([]string{"abc"}, 3)
+	require.Error(t, err)
+}
@@ -0,0 +1,303 @@
+package job
+
+import (
+	"cmp"
+	"encoding/json"
+	"fmt"
+	"reflect"
+	"slices"
+	"strings"
+	"time"
+
+	"github.com/sdivyansh59/digantara-backend-golang-assignment/app/shared"
+	"github.com/sdivyansh59/digantara-backend-golang-assignment/internal-lib/database/query"
+	"github.com/sdivyansh59/digantara-backend-golang-assignment/internal-lib/snowflake"
+	"github.com/sdivyansh59/digantara-backend-golang-assignment/internal-lib/utils"
+)
+
+const defaultPageSize = 50
+
+// Filter selects jobs in IRepository.Filter. Zero fields do not restrict the selection.
+type Filter struct {
+	Statuses      []shared.JobStatus
+	CreatedBy     string
+	ScheduledFrom int64                  // Unix timestamp, inclusive
+	ScheduledTo   int64                  // Unix timestamp, inclusive
+	NamePrefix    string                 // name starts with the prefix
+	Attributes    map[string]interface{} // attributes contain this JSON object
+	DependsOn     snowflake.ID           // jobs depending on this job
+
+	Sort  string // id, scheduled_at or created_at, ties are broken by id
+	Desc  bool
+	After *Job // only jobs sorting strictly after this one, e.g. the last job of the previous page
+	Limit int
+}
+
+// searchOptions translates the filter into the search options of the Postgres repository.
+func (f *Filter) searchOptions() ([]query.SearchOption, error) {
+	var options []query.SearchOption
+
+	if len(f.Statuses) > 0 {
+		options = append(options, query.WhereIn("job.status", f.Statuses))
+	}
+
+	if f.CreatedBy != "" {
+		options = append(options, query.Where("job.created_by", f.CreatedBy))
+	}
+
+	if f.ScheduledFrom > 0 {
+		options = append(options, query.WhereGte("job.scheduled_at", f.ScheduledFrom))
+	}
+
+	if f.ScheduledTo > 0 {
+		options = append(options, query.WhereLte("job.scheduled_at", f.ScheduledTo))
+	}
+
+	if f.NamePrefix != "" {
+		options = append(options, query.WhereHasPrefix("job.name", f.NamePrefix))
+	}
+
+	if f.Attributes != nil {
+		contains, err := query.WhereJSONContains("job.attributes", f.Attributes)
+		if err != nil {
+			return nil, err
+		}
+		options = append(options, contains)
+	}
+
+	if f.DependsOn != 0 {
+		options = append(options, query.WhereArrayContains("job.depends_on", f.DependsOn))
+	}
+
+	column := "job." + f.sort()
+	options = append(options, query.OrderBy(column, "job.id", f.Desc))
+
+	if f.After != nil {
+		switch f.sort() {
+		case "scheduled_at":
+			options = append(options, query.After(column, f.After.ScheduledAt, "job.id", f.After.Id, f.Desc))
+		case "created_at":
+			options = append(options, query.After(column, f.After.CreatedAt, "job.id", f.After.Id, f.Desc))
+		default:
+			options = append(options, query.After(column, f.After.Id, "job.id", f.After.Id, f.Desc))
+		}
+	}
+
+	if f.Limit > 0 {
+		options = append(options, query.Limit(f.Limit))
+	}
+
+	return options, nil
+}
+
+// matches tells whether job passes the conditions of the filter, like searchOptions does in Postgres.
+func (f *Filter) matches(job *Job) (bool, error) {
+	if len(f.Statuses) > 0 && !slices.Contains(f.Statuses, job.Status) {
+		return false, nil
+	}
+
+	if f.CreatedBy != "" && job.CreatedBy != f.CreatedBy {
+		return false, nil
+	}
+
+	if (f.ScheduledFrom > 0 && job.ScheduledAt < f.ScheduledFrom) || (f.ScheduledTo > 0 && job.ScheduledAt > f.ScheduledTo) {
+		return false, nil
+	}
+
+	if !strings.HasPrefix(job.Name, f.NamePrefix) {
+		return false, nil
+	}
+
+	if f.DependsOn != 0 && !slices.Contains(job.DependsOn, f.DependsOn) {
+		return false, nil
+	}
+
+	if f.After != nil && f.compare(job, f.After) <= 0 {
+		return false, nil
+	}
+
+	if f.Attributes == nil {
+		return true, nil
+	}
+
+	// Stored attributes went through JSON already, compare them with the JSON representation of the filter
+	var contained interface{}
+	if err := utils.CopyJSON(f.Attributes, &contained); err != nil {
+		return false, err
+	}
+
+	return containsJSON(job.Attributes, contained), nil
+}
+
+// compare orders jobs by the sort of the filter, like query.OrderBy.
+func (f *Filter) compare(a, b *Job) int {
+	var order int
+	switch f.sort() {
+	case "scheduled_at":
+		order = cmp.Compare(a.ScheduledAt, b.ScheduledAt)
+	case "created_at":
+		order = a.CreatedAt.Compare(b.CreatedAt)
+	}
+	order = cmp.Or(order, cmp.Compare(a.Id, b.Id))
+
+	if f.Desc {
+		return -order
+	}
+
+	return order
+}
+
+func (f *Filter) sort() string {
+	if f.Sort == "" {
+		return "id"
+	}
+
+	return f.Sort
+}
+
+// containsJSON tells whether the decoded JSON document contains the decoded JSON value contained, following the
+// rules of the jsonb @> operator: objects contain the keys of the other object with contained values, arrays contain
+// each element of the other array, scalars contain only equal scalars.
+func containsJSON(document, contained interface{}) bool {
+	switch contained := contained.(type) {
+	case map[string]interface{}:
+		object, ok := document.(map[string]interface{})
+		if !ok {
+			return false
+		}
+		for key, value := range contained {
+			if field, ok := object[key]; !ok || !containsJSON(field, value) {
+				return false
+			}
+		}
+		return true
+	case []interface{}:
+		array, ok := document.([]interface{})
+		if !ok {
+			return false
+		}
+		for _, value := range contained {
+			if !slices.ContainsFunc(array, func(element interface{}) bool { return containsJSON(element, value) }) {
+				return false
+			}
+		}
+		return true
+	default:
+		return reflect.DeepEqual(document, contained)
+	}
+}
+
+// jobCursor is the position of the last job of a page, encoded into the opaque next_cursor.
+type jobCursor struct {
+	Sort        string       `json:"s"`
+	ScheduledAt int64        `json:"sa,omitempty"`
+	CreatedAt   time.Time    `json:"ca,omitempty"`
+	ID          snowflake.ID `json:"id"`
+}
+
+// filter translates the filter query parameters into a repository filter.
+func (input *FilterJobsInput) filter() (*Filter, error) {
+	filter := &Filter{
+		CreatedBy:     input.CreatedBy,
+		ScheduledFrom: input.ScheduledFrom,
+		ScheduledTo:   input.ScheduledTo,
+		NamePrefix:    input.NamePrefix,
+	}
+
+	for _, status := range input.Status {
+		filter.Statuses = append(filter.Statuses, shared.JobStatus(status))
+	}
+
+	if input.ScheduledTo > 0 && input.ScheduledTo < input.ScheduledFrom {
+		return nil, fmt.Errorf("scheduled_to (%d) must not be before scheduled_from (%d)", input.ScheduledTo, input.ScheduledFrom)
+	}
+
+	if input.Attributes != "" {
+		if err := json.Unmarshal([]byte(input.Attributes), &filter.Attributes); err != nil {
+			return nil, fmt.Errorf("attributes must be a JSON object: %w", err)
+		}
+	}
+
+	return filter, nil
+}
+
+// page sets the sort, limit and keyset position of the requested page on filter.
+// One extra job is fetched to find out whether another page follows.
+func (input *FilterJobsInput) page(filter *Filter) error {
+	filter.Sort = strings.TrimPrefix(input.sort(), "-")
+	filter.Desc = strings.HasPrefix(input.sort(), "-")
+	filter.Limit = input.pageSize() + 1
+
+	if input.Cursor == "" {
+		return nil
+	}
+
+	var cursor jobCursor
+	if err := query.DecodeCursor(input.Cursor, &cursor); err != nil {
+		return err
+	}
+	if cursor.Sort != input.sort() {
+		return fmt.Errorf("cursor was created for sort %q, not %q", cursor.Sort, input.sort())
+	}
+	filter.After = &Job{Id: cursor.ID, ScheduledAt: cursor.ScheduledAt, CreatedAt: cursor.CreatedAt}
+
+	return nil
+}
+
+// nextCursor returns the cursor pointing after the last job of the page.
+func (input *FilterJobsInput) nextCursor(last *Job) (string, error) {
+	return query.EncodeCursor(jobCursor{
+		Sort:        input.sort(),
+		ScheduledAt: last.ScheduledAt,
+		CreatedAt:   last.CreatedAt,
+		ID:          last.Id,
+	})
+}
+
+func (input *FilterJobsInput) sort() string {
+	if input.Sort == "" {
+		return "id"
+	}
+
+	return input.Sort
+}
+
+func (input *FilterJobsInput) pageSize() int {
+	if input.Limit <= 0 {
+		return defaultPageSize
+	}
+
+	return input.Limit
+}
+
+// filter returns the repository filter for the requested page of dead-lettered jobs.
+// One extra job is fetched to find out whether another page follows.
+func (input *FilterDeadLetterJobsInput) filter() (*Filter, error) {
+	filter := &Filter{
+		Statuses: []shared.JobStatus{shared.JobStatusFailed},
+		Limit:    input.pageSize() + 1,
+	}
+
+	if input.Cursor != "" {
+		var cursor jobCursor
+		if err := query.DecodeCursor(input.Cursor, &cursor); err != nil {
+			return nil, err
+		}
+		filter.After = &Job{Id: cursor.ID}
+	}
+
+	return filter, nil
+}
+
+// nextCursor returns the cursor pointing after the last job of the page.
+func (input *FilterDeadLetterJobsInput) nextCursor(last *Job) (string, error) {
+	return query.EncodeCursor(jobCursor{Sort: "id", ID: last.Id})
+}
+
+func (input *FilterDeadLetterJobsInput) pageSize() int {
+	if input.Limit <= 0 {
+		return defaultPageSize
+	}
+
+	return input.Limit
+}
@@ -0,0 +1,524 @@
+package job
+
+import (
+	"cmp"
+	"context"
+	"fmt"
+	"slices"
+	"sync"
+	"time"
+
+	"github.com/sdivyansh59/digantara-backend-golang-assignment/app/shared"
+	"github.com/sdivyansh59/digantara-backend-golang-assignment/internal-lib/database"
+	"github.com/sdivyansh59/digantara-backend-golang-assignment/internal-lib/snowflake"
+	"github.com/sdivyansh59/digantara-backend-golang-assignment/internal-lib/utils"
+)
+
+// MemoryRepository keeps jobs in memory instead of Postgres, to run the service locally or in unit tests without a
+// database. It is safe for concurrent use and hands out copies, so callers never share a job with the repository.
+// Claims are atomic within the process, which is all a single instance needs.
+type MemoryRepository struct {
+	mu                 sync.Mutex
+	snowflakeGenerator *snowflake.Generator
+	jobs               map[snowflake.ID]*Job
+	changes            chan struct{}
+}
+
+func NewMemoryRepository(snowflakeGenerator *snowflake.Generator) *MemoryRepository {
+	return &MemoryRepository{
+		snowflakeGenerator: snowflakeGenerator,
+		jobs:               make(map[snowflake.ID]*Job),
+		changes:            make(chan struct{}, 1),
+	}
+}
+
+// Changes signals whenever a job may have become due earlier, like the job_wakeup notifications of the Postgres
+// trigger: a job was scheduled or rescheduled, or an upstream job succeeded. Signals are coalesced while unread.
+func (r *MemoryRepository) Changes() <-chan struct{} {
+	return r.changes
+}
+
+// Filter returns the jobs selected by filter, in its order.
+func (r *MemoryRepository) Filter(_ context.Context, filter *Filter) ([]Job, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	var jobs []Job
+	for _, job := range r.jobs {
+		matches, err := filter.matches(job)
+		if err != nil {
+			return nil, err
+		}
+		if !matches {
+			continue
+		}
+
+		copied, err := clone(job)
+		if err != nil {
+			return nil, err
+		}
+		jobs = append(jobs, copied)
+	}
+	slices.SortFunc(jobs, func(a, b Job) int { return filter.compare(&a, &b) })
+
+	if filter.Limit > 0 {
+		jobs = jobs[:min(filter.Limit, len(jobs))]
+	}
+
+	return jobs, nil
+}
+
+func (r *MemoryRepository) Create(_ context.Context, job *Job) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	job.Id = r.snowflakeGenerator.Next()
+	job.CreatedAt = time.Now()
+	job.UpdatedAt = time.Now()
+
+	stored, err := clone(job)
+	if err != nil {
+		return err
+	}
+	r.jobs[job.Id] = &stored
+
+	if job.Status == shared.JobStatusScheduled {
+		r.notify()
+	}
+
+	return nil
+}
+
+func (r *MemoryRepository) Update(_ context.Context, job *Job) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	previous, ok := r.jobs[job.Id]
+	if !ok {
+		return nil
+	}
+
+	job.UpdatedAt = time.Now()
+	stored, err := clone(job)
+	if err != nil {
+		return err
+	}
+	r.jobs[job.Id] = &stored
+	r.notifyChange(previous, &stored)
+
+	return nil
+}
+
+// RecordRun stores last_run_at of a finished run and counts it if it succeeded.
+func (r *MemoryRepository) RecordRun(_ context.Context, job *Job, succeeded bool) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	stored, ok := r.jobs[job.Id]
+	if !ok {
+		return database.ErrNotFound
+	}
+
+	job.UpdatedAt = time.Now()
+	stored.LastRunAt = utils.CopyPointer(job.LastRunAt)
+	stored.UpdatedAt = job.UpdatedAt
+	if succeeded {
+		stored.SuccessfulRuns++
+	}
+	job.SuccessfulRuns = stored.SuccessfulRuns
+
+	return nil
+}
+
+// Reschedule stores the schedule of the job after its occurrence ran, is retried or was skipped, releasing its lease.
+// Only the columns of the schedule are written, so successful_runs counted by RecordRun is left intact. A job paused
+// while it ran stays PAUSED, job.Status is set to the stored status.
+func (r *MemoryRepository) Reschedule(_ context.Context, job *Job) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	stored, ok := r.jobs[job.Id]
+	if !ok {
+		return nil
+	}
+
+	previous := *stored
+	job.UpdatedAt = time.Now()
+	if stored.Status == shared.JobStatusPaused {
+		job.Status = stored.Status
+	}
+	stored.Status = job.Status
+	stored.ScheduledAt = job.ScheduledAt
+	stored.OccurrenceAt = utils.CopyPointer(job.OccurrenceAt)
+	stored.Attempt = job.Attempt
+	stored.Owner = utils.CopyPointer(job.Owner)
+	stored.LeaseExpiresAt = utils.CopyPointer(job.LeaseExpiresAt)
+	stored.LastSucceededAt = utils.CopyPointer(job.LastSucceededAt)
+	stored.UpdatedAt = job.UpdatedAt
+	r.notifyChange(&previous, stored)
+
+	return nil
+}
+
+// SwapStatus sets the status of the job, provided neither its status nor its owner changed since the job was read.
+// Returns false if they did, e.g. because the scheduler claimed the job or its run finished in the meantime.
+func (r *MemoryRepository) SwapStatus(_ context.Context, job *Job, status shared.JobStatus) (bool, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	stored, ok := r.jobs[job.Id]
+	if !ok || stored.Status != job.Status || !equalStrings(stored.Owner, job.Owner) {
+		return false, nil
+	}
+
+	previous := *stored
+	job.Status = status
+	job.UpdatedAt = time.Now()
+	stored.Status = job.Status
+	stored.UpdatedAt = job.UpdatedAt
+	r.notifyChange(&previous, stored)
+
+	return true, nil
+}
+
+// UpdateIdle writes the given columns of the job, provided the job is still in status and owned by no instance.
+// Returns false otherwise.
+func (r *MemoryRepository) UpdateIdle(_ context.Context, job *Job, status shared.JobStatus, columns ...string) (bool, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	stored, ok := r.jobs[job.Id]
+	if !ok || stored.Status != status || stored.Owner != nil {
+		return false, nil
+	}
+
+	updated, err := clone(stored)
+	if err != nil {
+		return false, err
+	}
+	source, err := clone(job)
+	if err != nil {
+		return false, err
+	}
+	for _, column := range columns {
+		if err := copyColumn(&updated, &source, column); err != nil {
+			return false, err
+		}
+	}
+
+	job.UpdatedAt = time.Now()
+	updated.UpdatedAt = job.UpdatedAt
+	r.jobs[job.Id] = &updated
+	r.notifyChange(stored, &updated)
+
+	return true, nil
+}
+
+func (r *MemoryRepository) GetByID(_ context.Context, id snowflake.ID) (*Job, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	stored, ok := r.jobs[id]
+	if !ok {
+		return nil, database.ErrNotFound
+	}
+
+	job, err := clone(stored)
+	if err != nil {
+		return nil, err
+	}
+
+	return &job, nil
+}
+
+func (r *MemoryRepository) DeleteByID(_ context.Context, job *Job) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	delete(r.jobs, job.Id)
+	return nil
+}
+
+// GetNextJobToRun claims the most urgent due job by flipping it to RUNNING, leased to owner for the given duration.
+// Jobs are picked and ordered like in the Postgres repository, including priority aging.
+func (r *MemoryRepository) GetNextJobToRun(_ context.Context, aging time.Duration, owner string, lease time.Duration) (*Job, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	now := time.Now()
+	agingSeconds := max(int64(aging/time.Second), 1)
+	effectivePriority := func(job *Job) int64 {
+		return int64(job.Priority) + (now.Unix()-job.ScheduledAt)/agingSeconds
+	}
+
+	var next *Job
+	for _, job := range r.jobs {
+		if job.Status != shared.JobStatusScheduled || job.ScheduledAt > now.Unix() || !r.dependenciesSucceeded(job) {
+			continue
+		}
+
+		if next == nil || cmp.Or(
+			cmp.Compare(effectivePriority(next), effectivePriority(job)),
+			cmp.Compare(job.ScheduledAt, next.ScheduledAt),
+			cmp.Compare(job.Id, next.Id),
+		) < 0 {
+			next = job
+		}
+	}
+	if next == nil {
+		return nil, nil
+	}
+
+	leaseExpiresAt := now.Add(lease)
+	next.Status = shared.JobStatusRunning
+	next.Owner = &owner
+	next.LeaseExpiresAt = &leaseExpiresAt
+	next.UpdatedAt = now
+
+	job, err := clone(next)
+	if err != nil {
+		return nil, err
+	}
+
+	return &job, nil
+}
+
+// RenewLease extends the lease of the running job held by its owner, also while the job is paused during its run.
+// Returns false if the job is no longer leased to the owner.
+func (r *MemoryRepository) RenewLease(_ context.Context, job *Job, lease time.Duration) (bool, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	stored, ok := r.jobs[job.Id]
+	if !ok || (stored.Status != shared.JobStatusRunning && stored.Status != shared.JobStatusPaused) ||
+		stored.Owner == nil || job.Owner == nil || *stored.Owner != *job.Owner {
+		return false, nil
+	}
+
+	leaseExpiresAt := time.Now().Add(lease)
+	stored.LeaseExpiresAt = &leaseExpiresAt
+	job.LeaseExpiresAt = utils.CopyPointer(stored.LeaseExpiresAt)
+
+	return true, nil
+}
+
+// ClaimExpiredLeases takes over up to limit RUNNING jobs whose lease expired, leasing them to owner.
+// Running jobs without a lease count as expired. Jobs paused during their run are reaped as well, they stay PAUSED.
+func (r *MemoryRepository) ClaimExpiredLeases(_ context.Context, owner string, lease time.Duration, limit int) ([]Job, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	now := time.Now()
+
+	var expired []*Job
+	for _, job := range r.jobs {
+		running := job.Status == shared.JobStatusRunning && (job.LeaseExpiresAt == nil || job.LeaseExpiresAt.Before(now))
+		paused := job.Status == shared.JobStatusPaused && job.LeaseExpiresAt != nil && job.LeaseExpiresAt.Before(now)
+		if running || paused {
+			expired = append(expired, job)
+		}
+	}
+	slices.SortFunc(expired, func(a, b *Job) int {
+		switch {
+		case a.LeaseExpiresAt == nil && b.LeaseExpiresAt == nil:
+			return 0
+		case a.LeaseExpiresAt == nil:
+			return -1
+		case b.LeaseExpiresAt == nil:
+			return 1
+		default:
+			return a.LeaseExpiresAt.Compare(*b.LeaseExpiresAt)
+		}
+	})
+
+	leaseExpiresAt := now.Add(lease)
+	jobs := make([]Job, 0, min(limit, len(expired)))
+	for _, job := range expired[:min(limit, len(expired))] {
+		job.Owner = &owner
+		job.LeaseExpiresAt = &leaseExpiresAt
+		job.UpdatedAt = now
+
+		copied, err := clone(job)
+		if err != nil {
+			return nil, err
+		}
+		jobs = append(jobs, copied)
+	}
+
+	return jobs, nil
+}
+
+// GetNextJobScheduledTime returns the scheduled time of the earliest job waiting to run, skipping jobs still waiting
+// for their upstream jobs. Returns nil if no job is scheduled.
+func (r *MemoryRepository) GetNextJobScheduledTime(_ context.Context) (*int64, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	var nextRunAt *int64
+	for _, job := range r.jobs {
+		if job.Status != shared.JobStatusScheduled || !r.dependenciesSucceeded(job) {
+			continue
+		}
+		if nextRunAt == nil || job.ScheduledAt < *nextRunAt {
+			scheduledAt := job.ScheduledAt
+			nextRunAt = &scheduledAt
+		}
+	}
+
+	return nextRunAt, nil
+}
+
+// GetDependencyGraph returns every job that depends on or is depended on by another job.
+func (r *MemoryRepository) GetDependencyGraph(_ context.Context) ([]Job, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	upstream := make(map[snowflake.ID]bool)
+	for _, job := range r.jobs {
+		for _, id := range job.DependsOn {
+			upstream[id] = true
+		}
+	}
+
+	var jobs []Job
+	for _, job := range r.jobs {
+		if len(job.DependsOn) == 0 && !upstream[job.Id] {
+			continue
+		}
+
+		copied, err := clone(job)
+		if err != nil {
+			return nil, err
+		}
+		jobs = append(jobs, copied)
+	}
+	slices.SortFunc(jobs, func(a, b Job) int { return cmp.Compare(a.Id, b.Id) })
+
+	return jobs, nil
+}
+
+// dependenciesSucceeded tells whether every upstream job of job succeeded after the job's own last successful run,
+// i.e. within the current cycle. Upstream jobs that no longer exist are ignored. Must be called with mu held.
+func (r *MemoryRepository) dependenciesSucceeded(job *Job) bool {
+	cycleStart := job.CreatedAt
+	if job.LastSucceededAt != nil {
+		cycleStart = *job.LastSucceededAt
+	}
+
+	for _, id := range job.DependsOn {
+		upstream, ok := r.jobs[id]
+		if ok && (upstream.LastSucceededAt == nil || !upstream.LastSucceededAt.After(cycleStart)) {
+			return false
+		}
+	}
+
+	return true
+}
+
+// notifyChange signals Changes under the conditions of the job_wakeup trigger. Must be called with mu held.
+func (r *MemoryRepository) notifyChange(previous, updated *Job) {
+	rescheduled := updated.Status == shared.JobStatusScheduled &&
+		(previous.Status != updated.Status ||
+			previous.ScheduledAt != updated.ScheduledAt ||
+			!slices.Equal(previous.DependsOn, updated.DependsOn))
+	upstreamSucceeded := !equalTimes(previous.LastSucceededAt, updated.LastSucceededAt)
+
+	if rescheduled || upstreamSucceeded {
+		r.notify()
+	}
+}
+
+func (r *MemoryRepository) notify() {
+	select {
+	case r.changes <- struct{}{}:
+	default:
+		// A signal is pending already
+	}
+}
+
+// copyColumn copies the field behind the column, one of those a job update writes, from src to dst.
+func copyColumn(dst, src *Job, column string) error {
+	switch column {
+	case "name":
+		dst.Name = src.Name
+	case "description":
+		dst.Description = src.Description
+	case "type":
+		dst.Type = src.Type
+	case "interval_time":
+		dst.IntervalTime = src.IntervalTime
+	case "cron_expression":
+		dst.CronExpression = src.CronExpression
+	case "timezone":
+		dst.Timezone = src.Timezone
+	case "scheduled_at":
+		dst.ScheduledAt = src.ScheduledAt
+	case "occurrence_at":
+		dst.OccurrenceAt = src.OccurrenceAt
+	case "attempt":
+		dst.Attempt = src.Attempt
+	case "status":
+		dst.Status = src.Status
+	case "attributes":
+		dst.Attributes = src.Attributes
+	case "timeout_seconds":
+		dst.TimeoutSeconds = src.TimeoutSeconds
+	case "retry_policy":
+		dst.RetryPolicy = src.RetryPolicy
+	case "depends_on":
+		dst.DependsOn = src.DependsOn
+	case "concurrency_policy":
+		dst.ConcurrencyPolicy = src.ConcurrencyPolicy
+	case "priority":
+		dst.Priority = src.Priority
+	case "misfire_policy":
+		dst.MisfirePolicy = src.MisfirePolicy
+	default:
+		return fmt.Errorf("column %s of job cannot be updated", column)
+	}
+
+	return nil
+}
+
+func equalStrings(a, b *string) bool {
+	if a == nil || b == nil {
+		return a == b
+	}
+
+	return *a == *b
+}
+
+func equalTimes(a, b *time.Time) bool {
+	if a == nil || b == nil {
+		return a == b
+	}
+
+	return a.Equal(*b)
+}
+
+// clone copies job along with the values its pointers, slices and attributes refer to, so neither side sees the
+// other's later changes.
+// The attributes are deep-copied through JSON, which also stores them the way a jsonb column would.
+func clone(job *Job) (Job, error) {
+	copied := *job
+	copied.Description = utils.CopyPointer(job.Description)
+	copied.IntervalTime = utils.CopyPointer(job.IntervalTime)
+	copied.CronExpression = utils.CopyPointer(job.CronExpression)
+	copied.OccurrenceAt = utils.CopyPointer(job.OccurrenceAt)
+	copied.LastRunAt = utils.CopyPointer(job.LastRunAt)
+	copied.LastSucceededAt = utils.CopyPointer(job.LastSucceededAt)
+	copied.DependsOn = slices.Clone(job.DependsOn)
+	copied.TimeoutSeconds = utils.CopyPointer(job.TimeoutSeconds)
+	copied.RetryPolicy = utils.CopyPointer(job.RetryPolicy)
+	copied.Owner = utils.CopyPointer(job.Owner)
+	copied.LeaseExpiresAt = utils.CopyPointer(job.LeaseExpiresAt)
+	copied.Attributes = nil
+
+	if job.Attributes != nil {
+		if err := utils.CopyJSON(job.Attributes, &copied.Attributes); err != nil {
+			return Job{}, fmt.Errorf("failed to encode attributes of job %s: %w", job.Id, err)
+		}
+	}
+
+	return copied, nil
+}
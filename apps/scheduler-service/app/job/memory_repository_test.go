@@ -0,0 +1,272 @@
+package job
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/sdivyansh59/digantara-backend-golang-assignment/app/shared"
+	"github.com/sdivyansh59/digantara-backend-golang-assignment/internal-lib/snowflake"
+	"github.com/sdivyansh59/digantara-backend-golang-assignment/internal-lib/utils"
+	"github.com/stretchr/testify/require"
+)
+
+func TestMemoryRepository_GetNextJobToRun(t *testing.T) {
+	ctx := context.Background()
+	generator, err := snowflake.NewGenerator(1)
+	require.NoError(t, err)
+	repository := NewMemoryRepository(generator)
+
+	now := time.Now().Unix()
+	create := func(name string, priority int, scheduledAt int64, dependsOn ...snowflake.ID) *Job {
+		job := &Job{Name: name, Status: shared.JobStatusScheduled, Priority: priority, ScheduledAt: scheduledAt, DependsOn: dependsOn, CreatedBy: "a@b.c"}
+		require.NoError(t, repository.Create(ctx, job))
+		return job
+	}
+
+	upstream := create("upstream", 0, now-30)
+	create("downstream", 100, now-30, upstream.Id)
+	create("future", 100, now+3600)
+	urgent := create("urgent", 10, now-5)
+
+	// The most urgent due job is claimed, leased to the owner
+	next, err := repository.GetNextJobToRun(ctx, time.Hour, "instance-1", time.Minute)
+	require.NoError(t, err)
+	require.Equal(t, urgent.Id, next.Id)
+	require.Equal(t, shared.JobStatusRunning, next.Status)
+	require.Equal(t, "instance-1", *next.Owner)
+	require.NotNil(t, next.LeaseExpiresAt)
+
+	// The dependent job waits until its upstream job succeeded, the future job until it is due
+	next, err = repository.GetNextJobToRun(ctx, time.Hour, "instance-1", time.Minute)
+	require.NoError(t, err)
+	require.Equal(t, upstream.Id, next.Id)
+
+	next, err = repository.GetNextJobToRun(ctx, time.Hour, "instance-1", time.Minute)
+	require.NoError(t, err)
+	require.Nil(t, next)
+
+	scheduledAt, err := repository.GetNextJobScheduledTime(ctx)
+	require.NoError(t, err)
+	require.Equal(t, now+3600, *scheduledAt)
+
+	succeededAt := time.Now().Add(time.Second)
+	upstream, err = repository.GetByID(ctx, upstream.Id)
+	require.NoError(t, err)
+	upstream.Status = shared.JobStatusCompleted
+	upstream.LastSucceededAt = &succeededAt
+	require.NoError(t, repository.Update(ctx, upstream))
+	require.Len(t, repository.Changes(), 1)
+
+	// Claimed with a lease that already expired, as if the instance died
+	next, err = repository.GetNextJobToRun(ctx, time.Hour, "instance-2", -time.Second)
+	require.NoError(t, err)
+	require.Equal(t, "downstream", next.Name)
+
+	expired, err := repository.ClaimExpiredLeases(ctx, "reaper", time.Minute, 10)
+	require.NoError(t, err)
+	require.Len(t, expired, 1)
+	require.Equal(t, next.Id, expired[0].Id)
+	require.Equal(t, "reaper", *expired[0].Owner)
+
+	// Only the owner renews the lease
+	renewed, err := repository.RenewLease(ctx, next, time.Minute)
+	require.NoError(t, err)
+	require.False(t, renewed)
+
+	renewed, err = repository.RenewLease(ctx, &expired[0], time.Minute)
+	require.NoError(t, err)
+	require.True(t, renewed)
+
+	running, err := repository.Filter(ctx, &Filter{Statuses: []shared.JobStatus{shared.JobStatusRunning}})
+	require.NoError(t, err)
+	require.Len(t, running, 2)
+	require.Equal(t, "downstream", running[0].Name)
+	require.Equal(t, urgent.Id, running[1].Id)
+}
+
+func TestMemoryRepository_PriorityAging(t *testing.T) {
+	ctx := context.Background()
+	generator, err := snowflake.NewGenerator(1)
+	require.NoError(t, err)
+	repository := NewMemoryRepository(generator)
+
+	now := time.Now().Unix()
+	waiting := &Job{Name: "waiting", Status: shared.JobStatusScheduled, Priority: 0, ScheduledAt: now - 600}
+	urgent := &Job{Name: "urgent", Status: shared.JobStatusScheduled, Priority: 5, ScheduledAt: now}
+	require.NoError(t, repository.Create(ctx, waiting))
+	require.NoError(t, repository.Create(ctx, urgent))
+
+	// Waiting 10 minutes with one point per minute beats priority 5
+	next, err := repository.GetNextJobToRun(ctx, time.Minute, "instance-1", time.Minute)
+	require.NoError(t, err)
+	require.Equal(t, waiting.Id, next.Id)
+}
+
+func TestMemoryRepository_Reschedule(t *testing.T) {
+	ctx := context.Background()
+	generator, err := snowflake.NewGenerator(1)
+	require.NoError(t, err)
+	repository := NewMemoryRepository(generator)
+
+	job := &Job{Name: "job", Status: shared.JobStatusScheduled, ScheduledAt: time.Now().Unix(), CreatedBy: "a@b.c"}
+	require.NoError(t, repository.Create(ctx, job))
+
+	claimed, err := repository.GetNextJobToRun(ctx, time.Hour, "instance-1", time.Minute)
+	require.NoError(t, err)
+
+	// A run finishing concurrently is counted while the claimed copy still holds the old count
+	concurrent := *claimed
+	require.NoError(t, repository.RecordRun(ctx, &concurrent, true))
+
+	claimed.Status = shared.JobStatusScheduled
+	claimed.StartOccurrence(claimed.ScheduledAt + 60)
+	claimed.Owner, claimed.LeaseExpiresAt = nil, nil
+	require.NoError(t, repository.RecordRun(ctx, claimed, true))
+	require.NoError(t, repository.Reschedule(ctx, claimed))
+
+	stored, err := repository.GetByID(ctx, job.Id)
+	require.NoError(t, err)
+	require.Equal(t, shared.JobStatusScheduled, stored.Status)
+	require.Equal(t, job.ScheduledAt+60, stored.ScheduledAt)
+	require.Nil(t, stored.Owner)
+	require.Equal(t, 2, stored.SuccessfulRuns)
+}
+
+func TestMemoryRepository_PauseWhileRunning(t *testing.T) {
+	ctx := context.Background()
+	generator, err := snowflake.NewGenerator(1)
+	require.NoError(t, err)
+	repository := NewMemoryRepository(generator)
+
+	job := &Job{Name: "job", Status: shared.JobStatusScheduled, ScheduledAt: time.Now().Unix(), CreatedBy: "a@b.c"}
+	require.NoError(t, repository.Create(ctx, job))
+
+	claimed, err := repository.GetNextJobToRun(ctx, time.Hour, "instance-1", time.Minute)
+	require.NoError(t, err)
+
+	// A stale read of the job does not pause it
+	swapped, err := repository.SwapStatus(ctx, job, shared.JobStatusPaused)
+	require.NoError(t, err)
+	require.False(t, swapped)
+
+	paused, err := repository.GetByID(ctx, job.Id)
+	require.NoError(t, err)
+	swapped, err = repository.SwapStatus(ctx, paused, shared.JobStatusPaused)
+	require.NoError(t, err)
+	require.True(t, swapped)
+
+	// The run keeps its lease, and the job stays paused once the run finished
+	renewed, err := repository.RenewLease(ctx, claimed, time.Minute)
+	require.NoError(t, err)
+	require.True(t, renewed)
+
+	claimed.Status = shared.JobStatusScheduled
+	claimed.StartOccurrence(claimed.ScheduledAt + 60)
+	claimed.Owner, claimed.LeaseExpiresAt = nil, nil
+	require.NoError(t, repository.Reschedule(ctx, claimed))
+	require.Equal(t, shared.JobStatusPaused, claimed.Status)
+
+	stored, err := repository.GetByID(ctx, job.Id)
+	require.NoError(t, err)
+	require.Equal(t, shared.JobStatusPaused, stored.Status)
+	require.Equal(t, job.ScheduledAt+60, stored.ScheduledAt)
+}
+
+func TestMemoryRepository_Filter(t *testing.T) {
+	ctx := context.Background()
+	generator, err := snowflake.NewGenerator(1)
+	require.NoError(t, err)
+	repository := NewMemoryRepository(generator)
+
+	create := func(name string, scheduledAt int64, attributes map[string]interface{}) *Job {
+		job := &Job{Name: name, Status: shared.JobStatusScheduled, ScheduledAt: scheduledAt, Attributes: attributes, CreatedBy: "a@b.c"}
+		require.NoError(t, repository.Create(ctx, job))
+		return job
+	}
+
+	report := create("report-daily", 300, map[string]interface{}{"team": "data", "tags": []interface{}{"critical", "nightly"}})
+	export := create("report-weekly", 100, map[string]interface{}{"team": "data", "retries": 3})
+	create("cleanup", 200, map[string]interface{}{"team": "ops"})
+
+	// Attributes match like jsonb containment, numbers compare by value
+	jobs, err := repository.Filter(ctx, &Filter{Attributes: map[string]interface{}{"tags": []interface{}{"critical"}}})
+	require.NoError(t, err)
+	require.Len(t, jobs, 1)
+	require.Equal(t, report.Id, jobs[0].Id)
+
+	jobs, err = repository.Filter(ctx, &Filter{Attributes: map[string]interface{}{"retries": 3.0}})
+	require.NoError(t, err)
+	require.Len(t, jobs, 1)
+	require.Equal(t, export.Id, jobs[0].Id)
+
+	// Pages follow the sort, continuing after the last job of the previous page
+	filter := &Filter{NamePrefix: "report", Sort: "scheduled_at", Desc: true, Limit: 1}
+	jobs, err = repository.Filter(ctx, filter)
+	require.NoError(t, err)
+	require.Len(t, jobs, 1)
+	require.Equal(t, report.Id, jobs[0].Id)
+
+	filter.After = &jobs[0]
+	jobs, err = repository.Filter(ctx, filter)
+	require.NoError(t, err)
+	require.Len(t, jobs, 1)
+	require.Equal(t, export.Id, jobs[0].Id)
+
+	filter.After = &jobs[0]
+	jobs, err = repository.Filter(ctx, filter)
+	require.NoError(t, err)
+	require.Empty(t, jobs)
+}
+
+func TestMemoryRepository_Clone(t *testing.T) {
+	ctx := context.Background()
+	generator, err := snowflake.NewGenerator(1)
+	require.NoError(t, err)
+	repository := NewMemoryRepository(generator)
+
+	http := map[string]interface{}{"url": "https://example.com"}
+	job := &Job{Name: "nested", Status: shared.JobStatusScheduled, Attributes: map[string]interface{}{"http": http},
+		Description: utils.ToPointer("original"), RetryPolicy: &RetryPolicy{MaxAttempts: 3}}
+	require.NoError(t, repository.Create(ctx, job))
+
+	// Neither the caller's nor a reader's changes to nested or pointed-to values reach the stored job
+	http["url"] = "https://changed.example.com"
+	*job.Description = "changed"
+	stored, err := repository.GetByID(ctx, job.Id)
+	require.NoError(t, err)
+	stored.Attributes["http"].(map[string]interface{})["url"] = "https://other.example.com"
+	stored.RetryPolicy.MaxAttempts = 5
+
+	stored, err = repository.GetByID(ctx, job.Id)
+	require.NoError(t, err)
+	require.Equal(t, "https://example.com", stored.Attributes["http"].(map[string]interface{})["url"])
+	require.Equal(t, "original", *stored.Description)
+	require.Equal(t, 3, stored.RetryPolicy.MaxAttempts)
+}
+
+func TestMemoryRepository_UpdateIdle(t *testing.T) {
+	ctx := context.Background()
+	generator, err := snowflake.NewGenerator(1)
+	require.NoError(t, err)
+	repository := NewMemoryRepository(generator)
+
+	job := &Job{Name: "due", Status: shared.JobStatusScheduled, ScheduledAt: time.Now().Unix() - 5}
+	require.NoError(t, repository.Create(ctx, job))
+	read, err := repository.GetByID(ctx, job.Id)
+	require.NoError(t, err)
+
+	// The scheduler claims the job between reading and writing it
+	_, err = repository.GetNextJobToRun(ctx, time.Minute, "instance-1", time.Minute)
+	require.NoError(t, err)
+
+	read.Name = "renamed"
+	updated, err := repository.UpdateIdle(ctx, read, shared.JobStatusScheduled, "name")
+	require.NoError(t, err)
+	require.False(t, updated)
+
+	stored, err := repository.GetByID(ctx, job.Id)
+	require.NoError(t, err)
+	require.Equal(t, "due", stored.Name)
+	require.Equal(t, shared.JobStatusRunning, stored.Status)
+}
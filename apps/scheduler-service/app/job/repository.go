@@ -2,42 +2,70 @@ package job
 
 import (
 	"context"
-	"fmt"
+	"database/sql"
+	"errors"
 	"time"
 
 	"github.com/sdivyansh59/digantara-backend-golang-assignment/app/setup/dbconfig"
 	"github.com/sdivyansh59/digantara-backend-golang-assignment/app/shared"
+	"github.com/sdivyansh59/digantara-backend-golang-assignment/internal-lib/database"
 	"github.com/sdivyansh59/digantara-backend-golang-assignment/internal-lib/database/crud"
-	"github.com/sdivyansh59/digantara-backend-golang-assignment/internal-lib/database/query"
 	"github.com/sdivyansh59/digantara-backend-golang-assignment/internal-lib/snowflake"
 	"github.com/uptrace/bun"
 )
 
 type IRepository interface {
-	Filter(ctx context.Context, option ...query.SearchOption) ([]Job, error)
+	Filter(ctx context.Context, filter *Filter) ([]Job, error)
 	Create(ctx context.Context, job *Job) error
 	Update(ctx context.Context, job *Job) error
+	RecordRun(ctx context.Context, job *Job, succeeded bool) error
+	Reschedule(ctx context.Context, job *Job) error
+	SwapStatus(ctx context.Context, job *Job, status shared.JobStatus) (bool, error)
+	UpdateIdle(ctx context.Context, job *Job, status shared.JobStatus, columns ...string) (bool, error)
 	GetByID(ctx context.Context, id snowflake.ID) (*Job, error)
 	DeleteByID(ctx context.Context, job *Job) error
-	GetNextJobToRun(ctx context.Context) (*Job, error)
+	GetNextJobToRun(ctx context.Context, aging time.Duration, owner string, lease time.Duration) (*Job, error)
+	RenewLease(ctx context.Context, job *Job, lease time.Duration) (bool, error)
+	ClaimExpiredLeases(ctx context.Context, owner string, lease time.Duration, limit int) ([]Job, error)
 	GetNextJobScheduledTime(ctx context.Context) (*int64, error)
+	GetDependencyGraph(ctx context.Context) ([]Job, error)
 }
 
+// dependenciesSucceeded restricts a query on job to jobs whose upstream jobs all succeeded after the job's own
+// last successful run, i.e. within the current cycle. Jobs without dependencies always pass.
+const dependenciesSucceeded = `NOT EXISTS (
+   SELECT 1 FROM job AS upstream
+   WHERE upstream.id = ANY(job.depends_on)
+   AND (upstream.last_succeeded_at IS NULL
+    OR upstream.last_succeeded_at <= COALESCE(job.last_succeeded_at, job.created_at))
+  )`
+
 type Repository struct {
 	db                 *bun.DB
 	snowflakeGenerator *snowflake.Generator
 	handler            *crud.Handler[Job, snowflake.ID]
 }
 
-func NewRepository(snowflakeGenerator *snowflake.Generator, jobSchedulerDB *dbconfig.JobSchedulerDB) IRepository {
+// NewRepository returns the Postgres repository, or the in-memory one if storage is memory.
+func NewRepository(snowflakeGenerator *snowflake.Generator, jobSchedulerDB *dbconfig.JobSchedulerDB, storage dbconfig.Storage) IRepository {
+	if storage == dbconfig.StorageMemory {
+		return NewMemoryRepository(snowflakeGenerator)
+	}
+
 	return &Repository{
+		db:                 jobSchedulerDB.DB,
 		snowflakeGenerator: snowflakeGenerator,
 		handler:            crud.NewHandler[Job, snowflake.ID](jobSchedulerDB.DB),
 	}
 }
 
-func (r *Repository) Filter(ctx context.Context, option ...query.SearchOption) ([]Job, error) {
-	return r.handler.Search(ctx, option...)
+func (r *Repository) Filter(ctx context.Context, filter *Filter) ([]Job, error) {
+	options, err := filter.searchOptions()
+	if err != nil {
+		return nil, err
+	}
+
+	return r.handler.Search(ctx, options...)
 }
 
 func (r *Repository) Create(ctx context.Context, job *Job) error {
@@ -53,6 +81,104 @@ func (r *Repository) Update(ctx context.Context, job *Job) error {
 	return r.handler.Update(ctx, job)
 }
 
+// RecordRun stores last_run_at of a finished run and counts it if it succeeded.
+// successful_runs is incremented in the database, so runs of the same job finishing together are all counted.
+func (r *Repository) RecordRun(ctx context.Context, job *Job, succeeded bool) error {
+	increment := 0
+	if succeeded {
+		increment = 1
+	}
+	job.UpdatedAt = time.Now()
+
+	return database.GetIDBFromContext(ctx, r.db).
+		NewUpdate().
+		Model(job).
+		Set("last_run_at = ?", job.LastRunAt).
+		Set("successful_runs = successful_runs + ?", increment).
+		Set("updated_at = ?", job.UpdatedAt).
+		WherePK().
+		Returning("successful_runs").
+		Scan(ctx)
+}
+
+// Reschedule stores the schedule of the job after its occurrence ran, is retried or was skipped, releasing its lease.
+// Only the columns of the schedule are written, so successful_runs counted by RecordRun is left intact. A job paused
+// while it ran stays PAUSED, job.Status is set to the stored status.
+func (r *Repository) Reschedule(ctx context.Context, job *Job) error {
+	job.UpdatedAt = time.Now()
+
+	return database.GetIDBFromContext(ctx, r.db).
+		NewUpdate().
+		Model(job).
+		Set("status = CASE WHEN status = ? THEN status ELSE ? END", shared.JobStatusPaused, job.Status).
+		Set("scheduled_at = ?", job.ScheduledAt).
+		Set("occurrence_at = ?", job.OccurrenceAt).
+		Set("attempt = ?", job.Attempt).
+		Set("owner = ?", job.Owner).
+		Set("lease_expires_at = ?", job.LeaseExpiresAt).
+		Set("last_succeeded_at = ?", job.LastSucceededAt).
+		Set("updated_at = ?", job.UpdatedAt).
+		WherePK().
+		Returning("status").
+		Scan(ctx)
+}
+
+// SwapStatus sets the status of the job, provided neither its status nor its owner changed since the job was read.
+// Returns false if they did, e.g. because the scheduler claimed the job or its run finished in the meantime.
+func (r *Repository) SwapStatus(ctx context.Context, job *Job, status shared.JobStatus) (bool, error) {
+	updatedAt := time.Now()
+
+	result, err := database.GetIDBFromContext(ctx, r.db).
+		NewUpdate().
+		Model((*Job)(nil)).
+		Set("status = ?", status).
+		Set("updated_at = ?", updatedAt).
+		Where("id = ?", job.Id).
+		Where("status = ?", job.Status).
+		Where("owner IS NOT DISTINCT FROM ?", job.Owner).
+		Exec(ctx)
+	if err != nil {
+		return false, err
+	}
+
+	swapped, err := result.RowsAffected()
+	if err != nil {
+		return false, err
+	}
+	if swapped == 0 {
+		return false, nil
+	}
+
+	job.Status = status
+	job.UpdatedAt = updatedAt
+	return true, nil
+}
+
+// UpdateIdle writes the given columns of the job, provided the job is still in status and owned by no instance, i.e.
+// the scheduler did not claim it since it was read. Returns false otherwise.
+func (r *Repository) UpdateIdle(ctx context.Context, job *Job, status shared.JobStatus, columns ...string) (bool, error) {
+	job.UpdatedAt = time.Now()
+
+	result, err := database.GetIDBFromContext(ctx, r.db).
+		NewUpdate().
+		Model(job).
+		Column(append(columns, "updated_at")...).
+		WherePK().
+		Where("status = ?", status).
+		Where("owner IS NULL").
+		Exec(ctx)
+	if err != nil {
+		return false, err
+	}
+
+	updated, err := result.RowsAffected()
+	if err != nil {
+		return false, err
+	}
+
+	return updated > 0, nil
+}
+
 func (r *Repository) GetByID(ctx context.Context, id snowflake.ID) (*Job, error) {
 	return r.handler.GetByID(ctx, id)
 }
@@ -61,11 +187,24 @@ func (r *Repository) DeleteByID(ctx context.Context, job *Job) error {
 	return r.handler.Delete(ctx, job)
 }
 
+// GetNextJobScheduledTime returns the scheduled time of the earliest job waiting to run.
+// Paused, running and finished jobs are not considered, neither are jobs still waiting for their upstream jobs.
+// Returns nil if no job is scheduled.
 func (r *Repository) GetNextJobScheduledTime(ctx context.Context) (*int64, error) {
 	var nextRunAt int64
-	sql := fmt.Sprintf("SELECT id, scheduled_at FROM job WHERE status = %s ORDER BY scheduled_at ASC LIMIT 1", shared.JobStatusScheduled)
 
-	err := r.db.NewRaw(sql).Scan(ctx, &nextRunAt)
+	err := database.GetIDBFromContext(ctx, r.db).
+		NewSelect().
+		Model((*Job)(nil)).
+		Column("scheduled_at").
+		Where("status = ?", shared.JobStatusScheduled).
+		Where(dependenciesSucceeded).
+		Order("scheduled_at ASC").
+		Limit(1).
+		Scan(ctx, &nextRunAt)
+	if errors.Is(err, sql.ErrNoRows) {
+		return nil, nil
+	}
 	if err != nil {
 		return nil, err
 	}
@@ -73,23 +212,117 @@ func (r *Repository) GetNextJobScheduledTime(ctx context.Context) (*int64, error
 	return &nextRunAt, nil
 }
 
-func (r *Repository) GetNextJobToRun(ctx context.Context) (*Job, error) {
-	getNextJob := fmt.Sprintf(`
-  UPDATE job 
-  SET status = '%s'
+// GetNextJobToRun claims the most urgent due job by flipping it to RUNNING, leased to owner for the given duration.
+// Only SCHEDULED jobs are picked, so paused jobs are skipped. Jobs with dependencies are only picked once all
+// their upstream jobs succeeded in the current cycle. Returns nil if no job is due.
+//
+// Due jobs are ordered by priority, then by scheduled_at. Every full aging interval a job has been waiting past its
+// scheduled time raises its priority by one, so low-priority jobs are not starved by a steady stream of urgent ones.
+func (r *Repository) GetNextJobToRun(ctx context.Context, aging time.Duration, owner string, lease time.Duration) (*Job, error) {
+	getNextJob := `
+  UPDATE job
+  SET status = ?, owner = ?, lease_expires_at = ?, updated_at = ?
   WHERE id = (
-   SELECT id FROM jobs
-   WHERE status = '%s' AND run_at <= NOW()
-   ORDER BY run_at ASC
+   SELECT id FROM job
+   WHERE status = ? AND scheduled_at <= ?
+   AND ` + dependenciesSucceeded + `
+   ORDER BY priority + (? - scheduled_at) / ? DESC, scheduled_at ASC, id ASC
    LIMIT 1
    FOR UPDATE SKIP LOCKED
   )
-  RETURNING *`, shared.JobStatusRunning, shared.JobStatusScheduled)
+  RETURNING *`
+
+	now := time.Now()
+	agingSeconds := max(int64(aging/time.Second), 1)
+
+	var job Job
+	err := database.GetIDBFromContext(ctx, r.db).
+		NewRaw(getNextJob, shared.JobStatusRunning, owner, now.Add(lease), now,
+			shared.JobStatusScheduled, now.Unix(), now.Unix(), agingSeconds).
+		Scan(ctx, &job)
+	if errors.Is(err, sql.ErrNoRows) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	return &job, nil
+}
+
+// RenewLease extends the lease of the running job held by its owner, also while the job is paused during its run.
+// Returns false if the job is no longer leased to the owner, e.g. because the lease expired and the job was reaped.
+func (r *Repository) RenewLease(ctx context.Context, job *Job, lease time.Duration) (bool, error) {
+	leaseExpiresAt := time.Now().Add(lease)
+
+	result, err := database.GetIDBFromContext(ctx, r.db).
+		NewUpdate().
+		Model((*Job)(nil)).
+		Set("lease_expires_at = ?", leaseExpiresAt).
+		Where("id = ?", job.Id).
+		Where("owner = ?", job.Owner).
+		Where("status IN (?)", bun.In([]shared.JobStatus{shared.JobStatusRunning, shared.JobStatusPaused})).
+		Exec(ctx)
+	if err != nil {
+		return false, err
+	}
+
+	renewed, err := result.RowsAffected()
+	if err != nil {
+		return false, err
+	}
+	if renewed == 0 {
+		return false, nil
+	}
+
+	job.LeaseExpiresAt = &leaseExpiresAt
+	return true, nil
+}
+
+// ClaimExpiredLeases takes over up to limit RUNNING jobs whose lease expired, leasing them to owner so no other
+// instance reaps them at the same time. Running jobs without a lease, claimed before leases existed, count as expired.
+// Jobs paused during their run are reaped as well once their lease expired, they stay PAUSED.
+func (r *Repository) ClaimExpiredLeases(ctx context.Context, owner string, lease time.Duration, limit int) ([]Job, error) {
+	claimExpired := `
+  UPDATE job
+  SET owner = ?, lease_expires_at = ?, updated_at = ?
+  WHERE id IN (
+   SELECT id FROM job
+   WHERE (status = ? AND (lease_expires_at IS NULL OR lease_expires_at < ?))
+    OR (status = ? AND lease_expires_at < ?)
+   ORDER BY lease_expires_at ASC NULLS FIRST
+   LIMIT ?
+   FOR UPDATE SKIP LOCKED
+  )
+  RETURNING *`
+
+	now := time.Now()
+
+	var jobs []Job
+	err := database.GetIDBFromContext(ctx, r.db).
+		NewRaw(claimExpired, owner, now.Add(lease), now, shared.JobStatusRunning, now, shared.JobStatusPaused, now, limit).
+		Scan(ctx, &jobs)
+	if err != nil && !errors.Is(err, sql.ErrNoRows) {
+		return nil, err
+	}
+
+	return jobs, nil
+}
+
+// GetDependencyGraph returns every job that depends on or is depended on by another job.
+func (r *Repository) GetDependencyGraph(ctx context.Context) ([]Job, error) {
+	var jobs []Job
 
-	_, err := r.db.NewRaw(getNextJob).Exec(ctx)
+	err := database.GetIDBFromContext(ctx, r.db).
+		NewSelect().
+		Model(&jobs).
+		Where("cardinality(job.depends_on) > 0").
+		WhereOr("job.id IN (SELECT unnest(depends_on) FROM job)").
+		Order("job.id ASC").
+		Scan(ctx)
 	if err != nil {
 		return nil, err
 	}
 
-	return nil, nil
+	return jobs, nil
 }
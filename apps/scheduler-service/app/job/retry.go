@@ -0,0 +1,103 @@
+package job
+
+import (
+	"fmt"
+	"math"
+	"math/rand/v2"
+	"time"
+)
+
+const (
+	defaultInitialBackoffSeconds = 10
+	defaultBackoffMultiplier     = 2
+	defaultMaxBackoffSeconds     = 60 * 60
+)
+
+// RetryPolicy decides whether and when a failed scheduled execution is tried again.
+// The n-th retry waits initial_backoff_seconds * multiplier^(n-1), capped at max_backoff_seconds.
+// Jitter randomises each wait by up to the given fraction in either direction.
+type RetryPolicy struct {
+	MaxAttempts           int     `json:"max_attempts" minimum:"1" maximum:"100" doc:"Maximum number of attempts including the first execution. Once used up a one-time job becomes FAILED, a recurring job continues with its next occurrence" example:"5"`
+	InitialBackoffSeconds int64   `json:"initial_backoff_seconds,omitempty" minimum:"0" doc:"Wait before the first retry in seconds (default: 10)" example:"30"`
+	Multiplier            float64 `json:"multiplier,omitempty" minimum:"0" doc:"Factor the wait grows by with every retry, at least 1 (default: 2)" example:"2"`
+	MaxBackoffSeconds     int64   `json:"max_backoff_seconds,omitempty" minimum:"0" doc:"Upper bound of the wait between retries in seconds (default: 3600)" example:"600"`
+	Jitter                float64 `json:"jitter,omitempty" minimum:"0" maximum:"1" doc:"Fraction by which each wait is randomised, between 0 and 1 (default: 0)" example:"0.2"`
+}
+
+// Validate checks the policy for values that cannot produce a sensible backoff.
+func (p *RetryPolicy) Validate() error {
+	if p.MaxAttempts < 1 {
+		return fmt.Errorf("invalid retry policy: max_attempts must be at least 1")
+	}
+	if p.InitialBackoffSeconds < 0 || p.MaxBackoffSeconds < 0 {
+		return fmt.Errorf("invalid retry policy: backoff must not be negative")
+	}
+	if p.Multiplier != 0 && p.Multiplier < 1 {
+		return fmt.Errorf("invalid retry policy: multiplier must be at least 1")
+	}
+	if p.Jitter < 0 || p.Jitter > 1 {
+		return fmt.Errorf("invalid retry policy: jitter must be between 0 and 1")
+	}
+	if p.MaxBackoffSeconds != 0 && p.MaxBackoffSeconds < p.InitialBackoffSeconds {
+		return fmt.Errorf("invalid retry policy: max_backoff_seconds must not be below initial_backoff_seconds")
+	}
+
+	return nil
+}
+
+// Backoff returns the wait before retrying after the given failed attempt (1 for the first execution).
+func (p *RetryPolicy) Backoff(attempt int) time.Duration {
+	return p.backoff(attempt, rand.Float64())
+}
+
+// backoff computes the wait for a random value in [0, 1), which spreads it over the jitter range.
+func (p *RetryPolicy) backoff(attempt int, random float64) time.Duration {
+	initial := float64(p.InitialBackoffSeconds)
+	if p.InitialBackoffSeconds == 0 {
+		initial = defaultInitialBackoffSeconds
+	}
+
+	multiplier := p.Multiplier
+	if multiplier == 0 {
+		multiplier = defaultBackoffMultiplier
+	}
+
+	maxBackoff := float64(p.MaxBackoffSeconds)
+	if p.MaxBackoffSeconds == 0 {
+		maxBackoff = math.Max(defaultMaxBackoffSeconds, initial)
+	}
+
+	seconds := math.Min(initial*math.Pow(multiplier, float64(max(attempt-1, 0))), maxBackoff)
+	seconds += seconds * p.Jitter * (2*random - 1)
+
+	return time.Duration(seconds * float64(time.Second))
+}
+
+// NextRetryAt returns when the job's current occurrence is tried again after its attempt failed at now
+// (Unix timestamp). The boolean is false once the job has no retry policy or all attempts are used up.
+func (j *Job) NextRetryAt(now time.Time) (int64, bool) {
+	if j.RetryPolicy == nil || j.Attempt >= j.RetryPolicy.MaxAttempts {
+		return 0, false
+	}
+
+	// scheduled_at has second precision, round up so a retry never fires before its backoff elapsed
+	retryAt := now.Add(j.RetryPolicy.Backoff(j.Attempt))
+	return int64(math.Ceil(float64(retryAt.UnixNano()) / float64(time.Second))), true
+}
+
+// Occurrence returns the occurrence the job is currently due for (Unix timestamp). It is the scheduled time, unless
+// the job is retrying a failed occurrence at a later time.
+func (j *Job) Occurrence() int64 {
+	if j.OccurrenceAt != nil {
+		return *j.OccurrenceAt
+	}
+
+	return j.ScheduledAt
+}
+
+// StartOccurrence schedules the job for a new occurrence at scheduledAt with a fresh set of attempts.
+func (j *Job) StartOccurrence(scheduledAt int64) {
+	j.ScheduledAt = scheduledAt
+	j.OccurrenceAt = nil
+	j.Attempt = 1
+}
@@ -0,0 +1,58 @@
+package job
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestRetryPolicy_Backoff(t *testing.T) {
+	policy := &RetryPolicy{MaxAttempts: 5, InitialBackoffSeconds: 10, Multiplier: 3, MaxBackoffSeconds: 60}
+
+	// random 0.5 is the middle of the jitter range, i.e. no jitter
+	require.Equal(t, 10*time.Second, policy.backoff(1, 0.5))
+	require.Equal(t, 30*time.Second, policy.backoff(2, 0.5))
+	require.Equal(t, 60*time.Second, policy.backoff(3, 0.5))
+	require.Equal(t, 60*time.Second, policy.backoff(4, 0.5))
+
+	policy.Jitter = 0.5
+	require.Equal(t, 5*time.Second, policy.backoff(1, 0))
+	require.Equal(t, 15*time.Second, policy.backoff(1, 1))
+
+	defaults := &RetryPolicy{MaxAttempts: 3}
+	require.Equal(t, 10*time.Second, defaults.backoff(1, 0.5))
+	require.Equal(t, 20*time.Second, defaults.backoff(2, 0.5))
+	require.Equal(t, time.Hour, defaults.backoff(20, 0.5))
+}
+
+func TestRetryPolicy_Validate(t *testing.T) {
+	require.NoError(t, (&RetryPolicy{MaxAttempts: 1}).Validate())
+	require.NoError(t, (&RetryPolicy{MaxAttempts: 3, InitialBackoffSeconds: 5, Multiplier: 1.5, MaxBackoffSeconds: 60, Jitter: 0.1}).Validate())
+
+	invalid := []*RetryPolicy{
+		{MaxAttempts: 0},
+		{MaxAttempts: 3, Multiplier: 0.5},
+		{MaxAttempts: 3, Jitter: 1.5},
+		{MaxAttempts: 3, InitialBackoffSeconds: 120, MaxBackoffSeconds: 60},
+	}
+	for _, policy := range invalid {
+		require.Error(t, policy.Validate(), "%+v", policy)
+	}
+}
+
+func TestJob_NextRetryAt(t *testing.T) {
+	now := time.Date(2024, 10, 11, 3, 0, 0, 0, time.UTC)
+
+	_, retry := (&Job{Attempt: 1}).NextRetryAt(now)
+	require.False(t, retry)
+
+	job := &Job{Attempt: 1, RetryPolicy: &RetryPolicy{MaxAttempts: 2, InitialBackoffSeconds: 30}}
+	retryAt, retry := job.NextRetryAt(now)
+	require.True(t, retry)
+	require.Equal(t, now.Add(30*time.Second).Unix(), retryAt)
+
+	job.Attempt = 2
+	_, retry = job.NextRetryAt(now)
+	require.False(t, retry)
+}
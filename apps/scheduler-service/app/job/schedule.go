@@ -0,0 +1,290 @@
+package job
+
+import (
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/robfig/cron/v3"
+)
+
+// DefaultTimezone is used for jobs created without an explicit timezone.
+const DefaultTimezone = "UTC"
+
+const minutesPerDay = 24 * 60
+
+// maxBackfillRuns bounds the number of runs a single backfill request queues
+const maxBackfillRuns = 1000
+
+// millisecondsThreshold is the smallest scheduled_at that can only be meant in milliseconds: as seconds it lies past
+// the year 5000. See migration 20241009000015_convert_scheduled_at_to_seconds.
+const millisecondsThreshold = 100_000_000_000
+
+// cronParser accepts the standard 5-field syntax, an optional leading seconds field
+// and descriptors such as @daily, @hourly or @every 90m.
+var cronParser = cron.NewParser(
+	cron.SecondOptional | cron.Minute | cron.Hour | cron.Dom | cron.Month | cron.Dow | cron.Descriptor,
+)
+
+// validateSeconds rejects scheduled times given in milliseconds, the unit scheduled_at used to have.
+func validateSeconds(scheduledAt int64) error {
+	if scheduledAt >= millisecondsThreshold {
+		return fmt.Errorf("scheduled_at must be a Unix timestamp in seconds, %d looks like milliseconds", scheduledAt)
+	}
+
+	return nil
+}
+
+// ParseCronExpression validates a cron expression and returns its schedule.
+func ParseCronExpression(expression string) (cron.Schedule, error) {
+	// The timezone is a property of the job, an inline CRON_TZ would silently override it
+	if strings.HasPrefix(expression, "TZ=") || strings.HasPrefix(expression, "CRON_TZ=") {
+		return nil, fmt.Errorf("invalid cron expression %q: use the timezone field instead of a TZ prefix", expression)
+	}
+
+	schedule, err := cronParser.Parse(expression)
+	if err != nil {
+		return nil, fmt.Errorf("invalid cron expression %q: %w", expression, err)
+	}
+
+	return schedule, nil
+}
+
+// LoadTimezone validates an IANA timezone name such as "Asia/Kolkata" or "Europe/Berlin".
+// An empty name stands for UTC.
+func LoadTimezone(name string) (*time.Location, error) {
+	if name == "" {
+		return time.UTC, nil
+	}
+
+	// "Local" would depend on the machine the scheduler happens to run on
+	if name == "Local" {
+		return nil, fmt.Errorf("invalid timezone %q: use an IANA timezone name", name)
+	}
+
+	location, err := time.LoadLocation(name)
+	if err != nil {
+		return nil, fmt.Errorf("invalid timezone %q: %w", name, err)
+	}
+
+	return location, nil
+}
+
+// Location returns the timezone the job's schedule is evaluated in, UTC if unset or unknown.
+func (j *Job) Location() *time.Location {
+	location, err := LoadTimezone(j.Timezone)
+	if err != nil {
+		return time.UTC
+	}
+
+	return location
+}
+
+// IsRecurring reports whether the job runs more than once.
+func (j *Job) IsRecurring() bool {
+	return j.CronExpression != nil || (j.IntervalTime != nil && *j.IntervalTime > 0)
+}
+
+// NextScheduledAt returns the run time following after (Unix timestamp).
+// The boolean is false for one-time jobs, which have no next run.
+//
+// Recurring schedules are evaluated in the job's timezone:
+//   - Cron expressions match the wall clock of the timezone, "0 9 * * *" in Europe/Berlin runs at 09:00 local
+//     time in summer and in winter. @every runs at a fixed elapsed duration regardless of the wall clock.
+//   - Intervals of whole days keep the wall-clock time of the previous run, other intervals are fixed durations.
+//
+// At DST transitions wall-clock times that do not exist (spring forward) run shifted forward by the length
+// of the gap, e.g. 02:30 runs at 03:30. Wall-clock times that occur twice (fall back) run once, at their
+// first occurrence.
+func (j *Job) NextScheduledAt(after time.Time) (int64, bool, error) {
+	location, err := LoadTimezone(j.Timezone)
+	if err != nil {
+		return 0, false, err
+	}
+
+	if j.CronExpression != nil {
+		schedule, err := ParseCronExpression(*j.CronExpression)
+		if err != nil {
+			return 0, false, err
+		}
+
+		next := nextCronOccurrence(schedule, after, location)
+		if next.IsZero() {
+			// The expression never matches again (e.g. 30th of February)
+			return 0, false, nil
+		}
+
+		return next.Unix(), true, nil
+	}
+
+	if j.IntervalTime != nil && *j.IntervalTime > 0 {
+		return j.addIntervals(after, 1, location).Unix(), true, nil
+	}
+
+	return 0, false, nil
+}
+
+// NextRunAfter returns the first scheduled time of the job that lies after now.
+// Recurring jobs keep the cadence of their current occurrence, missed runs are skipped instead of being fired as a
+// backlog. One-time jobs keep their scheduled time.
+func (j *Job) NextRunAfter(now time.Time) (int64, error) {
+	if !j.IsRecurring() || j.ScheduledAt > now.Unix() {
+		return j.ScheduledAt, nil
+	}
+
+	if j.CronExpression != nil {
+		next, ok, err := j.NextScheduledAt(now)
+		if err != nil || !ok {
+			return j.ScheduledAt, err
+		}
+
+		return next, nil
+	}
+
+	location, err := LoadTimezone(j.Timezone)
+	if err != nil {
+		return j.ScheduledAt, err
+	}
+
+	// Estimate the number of missed intervals, then correct for DST shifts of day based intervals
+	occurrence := j.Occurrence()
+	start := time.Unix(occurrence, 0)
+	interval := *j.IntervalTime * int64(time.Minute/time.Second)
+	missed := (now.Unix()-occurrence)/interval + 1
+
+	for !j.addIntervals(start, missed, location).After(now) {
+		missed++
+	}
+	for missed > 1 && j.addIntervals(start, missed-1, location).After(now) {
+		missed--
+	}
+
+	return j.addIntervals(start, missed, location).Unix(), nil
+}
+
+// MissedOccurrences counts the occurrences from the job's current occurrence up to now, at most limit.
+// It also returns the latest of them (Unix timestamp). One-time jobs have a single occurrence.
+func (j *Job) MissedOccurrences(now time.Time, limit int) (int, int64) {
+	count, last := 1, j.Occurrence()
+	for count < limit {
+		next, ok, err := j.NextScheduledAt(time.Unix(last, 0))
+		if err != nil || !ok || next > now.Unix() {
+			break
+		}
+
+		count++
+		last = next
+	}
+
+	return count, last
+}
+
+// OccurrencesBetween returns the occurrences of a recurring job within [from, to] (Unix timestamps).
+// Intervals keep the cadence of the job's current occurrence. Returns an error if there are more than limit occurrences.
+func (j *Job) OccurrencesBetween(from, to time.Time, limit int) ([]int64, error) {
+	if !j.IsRecurring() {
+		return nil, fmt.Errorf("job %s is not recurring", j.Id)
+	}
+
+	var next int64
+	if j.CronExpression != nil {
+		first, ok, err := j.NextScheduledAt(from.Add(-time.Second))
+		if err != nil || !ok {
+			return nil, err
+		}
+		next = first
+	} else {
+		location, err := LoadTimezone(j.Timezone)
+		if err != nil {
+			return nil, err
+		}
+
+		// Step from the scheduled time to the first interval at or after from, in either direction
+		occurrence := j.Occurrence()
+		start := time.Unix(occurrence, 0)
+		interval := *j.IntervalTime * int64(time.Minute/time.Second)
+		n := (from.Unix() - occurrence) / interval
+
+		for j.addIntervals(start, n, location).Before(from) {
+			n++
+		}
+		for !j.addIntervals(start, n-1, location).Before(from) {
+			n--
+		}
+		next = j.addIntervals(start, n, location).Unix()
+	}
+
+	var occurrences []int64
+	for next <= to.Unix() {
+		if len(occurrences) == limit {
+			return nil, fmt.Errorf("more than %d occurrences between %d and %d", limit, from.Unix(), to.Unix())
+		}
+		occurrences = append(occurrences, next)
+
+		following, ok, err := j.NextScheduledAt(time.Unix(next, 0))
+		if err != nil {
+			return nil, err
+		}
+		if !ok {
+			break
+		}
+		next = following
+	}
+
+	return occurrences, nil
+}
+
+// addIntervals adds n intervals to t, whole days are added on the wall clock of the location.
+func (j *Job) addIntervals(t time.Time, n int64, location *time.Location) time.Time {
+	minutes := *j.IntervalTime * n
+	if *j.IntervalTime%minutesPerDay != 0 {
+		return t.Add(time.Duration(minutes) * time.Minute)
+	}
+
+	return fromWallClock(toWallClock(t, location).AddDate(0, 0, int(minutes/minutesPerDay)), location)
+}
+
+// nextCronOccurrence evaluates the schedule on the wall clock of the location, see NextScheduledAt.
+func nextCronOccurrence(schedule cron.Schedule, after time.Time, location *time.Location) time.Time {
+	if _, ok := schedule.(cron.ConstantDelaySchedule); ok {
+		return schedule.Next(after)
+	}
+
+	wall := toWallClock(after, location)
+	for {
+		wall = schedule.Next(wall)
+		if wall.IsZero() {
+			return wall
+		}
+
+		// A wall-clock time that occurred twice may already have run in its first occurrence
+		if next := fromWallClock(wall, location); next.After(after) {
+			return next
+		}
+	}
+}
+
+// toWallClock returns the wall-clock reading of t in the location, carried in a UTC time so it can be
+// stepped through without DST gaps or repeats.
+func toWallClock(t time.Time, location *time.Location) time.Time {
+	local := t.In(location)
+	return time.Date(local.Year(), local.Month(), local.Day(), local.Hour(), local.Minute(), local.Second(), 0, time.UTC)
+}
+
+// fromWallClock maps a wall-clock reading back onto the location.
+// Skipped times move forward by the length of the gap, repeated times resolve to their first occurrence.
+func fromWallClock(wall time.Time, location *time.Location) time.Time {
+	t := time.Date(wall.Year(), wall.Month(), wall.Day(), wall.Hour(), wall.Minute(), wall.Second(), 0, location)
+
+	// Clocks went back within the last day, check whether the same wall-clock time already occurred earlier
+	_, offset := t.Zone()
+	_, previousOffset := t.Add(-24 * time.Hour).Zone()
+	if previousOffset > offset {
+		earlier := t.Add(-time.Duration(previousOffset-offset) * time.Second)
+		if toWallClock(earlier, location).Equal(wall) {
+			return earlier
+		}
+	}
+
+	return t
+}
@@ -0,0 +1,203 @@
+package job
+
+import (
+	"testing"
+	"time"
+
+	"github.com/sdivyansh59/digantara-backend-golang-assignment/internal-lib/utils"
+	"github.com/stretchr/testify/require"
+)
+
+func TestParseCronExpression(t *testing.T) {
+	valid := []string{"30 2 * * 1-5", "0 30 2 * * 1-5", "@daily", "@hourly", "@every 90m", "0 0 1 * *"}
+	for _, expression := range valid {
+		_, err := ParseCronExpression(expression)
+		require.NoError(t, err, expression)
+	}
+
+	invalid := []string{"", "* * *", "61 * * * *", "@sometimes"}
+	for _, expression := range invalid {
+		_, err := ParseCronExpression(expression)
+		require.Error(t, err, expression)
+	}
+}
+
+func TestJob_NextScheduledAt(t *testing.T) {
+	// Friday 2024-10-11 03:00 UTC
+	after := time.Date(2024, 10, 11, 3, 0, 0, 0, time.UTC)
+
+	weekdays := &Job{CronExpression: utils.ToPointer("30 2 * * 1-5")}
+	next, ok, err := weekdays.NextScheduledAt(after)
+	require.NoError(t, err)
+	require.True(t, ok)
+	require.Equal(t, time.Date(2024, 10, 14, 2, 30, 0, 0, time.UTC).Unix(), next)
+
+	monthly := &Job{CronExpression: utils.ToPointer("0 0 1 * *")}
+	next, ok, err = monthly.NextScheduledAt(after)
+	require.NoError(t, err)
+	require.True(t, ok)
+	require.Equal(t, time.Date(2024, 11, 1, 0, 0, 0, 0, time.UTC).Unix(), next)
+
+	interval := &Job{IntervalTime: utils.ToPointer(int64(60))}
+	next, ok, err = interval.NextScheduledAt(after)
+	require.NoError(t, err)
+	require.True(t, ok)
+	require.Equal(t, after.Add(time.Hour).Unix(), next)
+
+	oneTime := &Job{}
+	_, ok, err = oneTime.NextScheduledAt(after)
+	require.NoError(t, err)
+	require.False(t, ok)
+}
+
+func TestJob_NextRunAfter(t *testing.T) {
+	now := time.Date(2024, 10, 11, 3, 0, 0, 0, time.UTC)
+
+	// Hourly job last due at 00:15 skips the missed runs and keeps its cadence
+	interval := &Job{
+		IntervalTime: utils.ToPointer(int64(60)),
+		ScheduledAt:  time.Date(2024, 10, 11, 0, 15, 0, 0, time.UTC).Unix(),
+	}
+	next, err := interval.NextRunAfter(now)
+	require.NoError(t, err)
+	require.Equal(t, time.Date(2024, 10, 11, 3, 15, 0, 0, time.UTC).Unix(), next)
+
+	daily := &Job{
+		CronExpression: utils.ToPointer("@daily"),
+		ScheduledAt:    time.Date(2024, 10, 9, 0, 0, 0, 0, time.UTC).Unix(),
+	}
+	next, err = daily.NextRunAfter(now)
+	require.NoError(t, err)
+	require.Equal(t, time.Date(2024, 10, 12, 0, 0, 0, 0, time.UTC).Unix(), next)
+
+	// A retry of the 00:15 occurrence at 01:02 keeps the cadence of the occurrence
+	retried := &Job{
+		IntervalTime: utils.ToPointer(int64(60)),
+		ScheduledAt:  time.Date(2024, 10, 11, 1, 2, 0, 0, time.UTC).Unix(),
+		OccurrenceAt: utils.ToPointer(time.Date(2024, 10, 11, 0, 15, 0, 0, time.UTC).Unix()),
+	}
+	next, err = retried.NextRunAfter(now)
+	require.NoError(t, err)
+	require.Equal(t, time.Date(2024, 10, 11, 3, 15, 0, 0, time.UTC).Unix(), next)
+
+	future := &Job{IntervalTime: utils.ToPointer(int64(60)), ScheduledAt: now.Add(time.Minute).Unix()}
+	next, err = future.NextRunAfter(now)
+	require.NoError(t, err)
+	require.Equal(t, future.ScheduledAt, next)
+}
+
+func TestJob_NextScheduledAt_Timezone(t *testing.T) {
+	after := time.Date(2024, 10, 11, 0, 0, 0, 0, time.UTC)
+
+	kolkata := &Job{CronExpression: utils.ToPointer("0 9 * * *"), Timezone: "Asia/Kolkata"}
+	next, ok, err := kolkata.NextScheduledAt(after)
+	require.NoError(t, err)
+	require.True(t, ok)
+	require.Equal(t, time.Date(2024, 10, 11, 3, 30, 0, 0, time.UTC).Unix(), next)
+
+	unknown := &Job{CronExpression: utils.ToPointer("0 9 * * *"), Timezone: "Mars/Olympus"}
+	_, _, err = unknown.NextScheduledAt(after)
+	require.Error(t, err)
+
+	_, err = LoadTimezone("Local")
+	require.Error(t, err)
+}
+
+func TestJob_NextScheduledAt_DST(t *testing.T) {
+	berlin, err := time.LoadLocation("Europe/Berlin")
+	require.NoError(t, err)
+
+	next := func(job *Job, after time.Time) time.Time {
+		value, ok, err := job.NextScheduledAt(after)
+		require.NoError(t, err)
+		require.True(t, ok)
+		return time.Unix(value, 0).In(berlin)
+	}
+
+	// 09:00 stays 09:00 local time across the spring forward transition on 2024-03-31
+	daily := &Job{CronExpression: utils.ToPointer("0 9 * * *"), Timezone: "Europe/Berlin"}
+	require.Equal(t, "2024-03-31T09:00:00+02:00", next(daily, time.Date(2024, 3, 30, 9, 0, 0, 0, berlin)).Format(time.RFC3339))
+
+	// 02:30 does not exist on 2024-03-31 and runs shifted forward by the gap
+	skipped := &Job{CronExpression: utils.ToPointer("30 2 * * *"), Timezone: "Europe/Berlin"}
+	first := next(skipped, time.Date(2024, 3, 30, 12, 0, 0, 0, berlin))
+	require.Equal(t, "2024-03-31T03:30:00+02:00", first.Format(time.RFC3339))
+	require.Equal(t, "2024-04-01T02:30:00+02:00", next(skipped, first).Format(time.RFC3339))
+
+	// 02:30 occurs twice on 2024-10-27 and runs only at its first occurrence
+	first = next(skipped, time.Date(2024, 10, 26, 12, 0, 0, 0, berlin))
+	require.Equal(t, "2024-10-27T02:30:00+02:00", first.Format(time.RFC3339))
+	require.Equal(t, "2024-10-28T02:30:00+01:00", next(skipped, first).Format(time.RFC3339))
+
+	// Day based intervals keep the wall-clock time, other intervals are fixed durations
+	dayInterval := &Job{IntervalTime: utils.ToPointer(int64(1440)), Timezone: "Europe/Berlin"}
+	require.Equal(t, "2024-03-31T09:00:00+02:00", next(dayInterval, time.Date(2024, 3, 30, 9, 0, 0, 0, berlin)).Format(time.RFC3339))
+
+	hourInterval := &Job{IntervalTime: utils.ToPointer(int64(60)), Timezone: "Europe/Berlin"}
+	require.Equal(t, "2024-03-31T03:30:00+02:00", next(hourInterval, time.Date(2024, 3, 31, 1, 30, 0, 0, berlin)).Format(time.RFC3339))
+
+	// Resuming a daily job after the transition keeps 09:00 local time
+	dayInterval.ScheduledAt = time.Date(2024, 3, 28, 9, 0, 0, 0, berlin).Unix()
+	resumed, err := dayInterval.NextRunAfter(time.Date(2024, 4, 2, 12, 0, 0, 0, berlin))
+	require.NoError(t, err)
+	require.Equal(t, "2024-04-03T09:00:00+02:00", time.Unix(resumed, 0).In(berlin).Format(time.RFC3339))
+}
+
+func TestJob_MissedOccurrences(t *testing.T) {
+	now := time.Date(2024, 10, 11, 3, 0, 0, 0, time.UTC)
+
+	hourly := &Job{
+		CronExpression: utils.ToPointer("@hourly"),
+		ScheduledAt:    time.Date(2024, 10, 10, 22, 0, 0, 0, time.UTC).Unix(),
+	}
+	count, last := hourly.MissedOccurrences(now, 100)
+	require.Equal(t, 6, count)
+	require.Equal(t, now.Unix(), last)
+
+	count, last = hourly.MissedOccurrences(now, 3)
+	require.Equal(t, 3, count)
+	require.Equal(t, time.Date(2024, 10, 11, 0, 0, 0, 0, time.UTC).Unix(), last)
+
+	oneTime := &Job{ScheduledAt: time.Date(2024, 10, 10, 22, 0, 0, 0, time.UTC).Unix()}
+	count, last = oneTime.MissedOccurrences(now, 100)
+	require.Equal(t, 1, count)
+	require.Equal(t, oneTime.ScheduledAt, last)
+}
+
+func TestJob_OccurrencesBetween(t *testing.T) {
+	from := time.Date(2024, 10, 7, 0, 0, 0, 0, time.UTC)
+	to := time.Date(2024, 10, 9, 23, 59, 59, 0, time.UTC)
+
+	daily := &Job{CronExpression: utils.ToPointer("30 2 * * *")}
+	occurrences, err := daily.OccurrencesBetween(from, to, 100)
+	require.NoError(t, err)
+	require.Equal(t, []int64{
+		time.Date(2024, 10, 7, 2, 30, 0, 0, time.UTC).Unix(),
+		time.Date(2024, 10, 8, 2, 30, 0, 0, time.UTC).Unix(),
+		time.Date(2024, 10, 9, 2, 30, 0, 0, time.UTC).Unix(),
+	}, occurrences)
+
+	// Intervals keep the cadence of the scheduled time, also for ranges before it
+	everyDay := &Job{
+		IntervalTime: utils.ToPointer(int64(1440)),
+		ScheduledAt:  time.Date(2024, 10, 20, 6, 0, 0, 0, time.UTC).Unix(),
+	}
+	occurrences, err = everyDay.OccurrencesBetween(from, to, 100)
+	require.NoError(t, err)
+	require.Equal(t, []int64{
+		time.Date(2024, 10, 7, 6, 0, 0, 0, time.UTC).Unix(),
+		time.Date(2024, 10, 8, 6, 0, 0, 0, time.UTC).Unix(),
+		time.Date(2024, 10, 9, 6, 0, 0, 0, time.UTC).Unix(),
+	}, occurrences)
+
+	// The start of the range is inclusive
+	occurrences, err = everyDay.OccurrencesBetween(time.Date(2024, 10, 7, 6, 0, 0, 0, time.UTC), from.Add(29*time.Hour), 100)
+	require.NoError(t, err)
+	require.Len(t, occurrences, 1)
+
+	_, err = daily.OccurrencesBetween(from, to, 2)
+	require.Error(t, err)
+
+	_, err = (&Job{}).OccurrencesBetween(from, to, 100)
+	require.Error(t, err)
+}
@@ -11,18 +11,32 @@ import (
 type Job struct {
 	bun.BaseModel `bun:"table:job,alias:job"`
 
-	Id             snowflake.ID           `bun:"id,pk,notnull"`
-	Name           string                 `bun:"name,notnull"`
-	Description    *string                `bun:"description"`
-	Status         shared.JobStatus       `bun:"status,notnull"`
-	IntervalTime   *int64                 `bun:"interval_time"`        // nullable for one-time jobs
-	ScheduledAt    int64                  `bun:"scheduled_at,notnull"` // Unix timestamp in milliseconds
-	LastRunAt      *time.Time             `bun:"last_run_at"`
-	SuccessfulRuns int                    `bun:"successful_runs,notnull,default:0"`
-	Attributes     map[string]interface{} `bun:"attributes,type:jsonb"` // explicitly specify JSONB type
-	CreatedBy      string                 `bun:"created_by,notnull"`
-	CreatedAt      time.Time              `bun:"created_at,notnull,default:current_timestamp"`
-	UpdatedAt      time.Time              `bun:"updated_at,notnull,default:current_timestamp"`
+	Id                snowflake.ID             `bun:"id,pk,notnull"`
+	Name              string                   `bun:"name,notnull"`
+	Description       *string                  `bun:"description"`
+	Status            shared.JobStatus         `bun:"status,notnull"`
+	Type              string                   `bun:"type,notnull,default:'noop'"`    // executor running the job
+	IntervalTime      *int64                   `bun:"interval_time"`                  // nullable for one-time jobs
+	CronExpression    *string                  `bun:"cron_expression"`                // nullable, alternative to interval_time
+	Timezone          string                   `bun:"timezone,notnull,default:'UTC'"` // IANA timezone for recurring schedules
+	ScheduledAt       int64                    `bun:"scheduled_at,notnull"`           // Unix timestamp in seconds
+	OccurrenceAt      *int64                   `bun:"occurrence_at"`                  // occurrence being retried at scheduled_at, nullable
+	LastRunAt         *time.Time               `bun:"last_run_at"`
+	SuccessfulRuns    int                      `bun:"successful_runs,notnull,default:0"`
+	LastSucceededAt   *time.Time               `bun:"last_succeeded_at"` // end of the last successful scheduled run
+	DependsOn         []snowflake.ID           `bun:"depends_on,array"`  // upstream jobs that must succeed first
+	ConcurrencyPolicy shared.ConcurrencyPolicy `bun:"concurrency_policy,notnull,default:'Allow'"`
+	Priority          int                      `bun:"priority,notnull,default:0"` // higher runs first when several jobs are due
+	MisfirePolicy     shared.MisfirePolicy     `bun:"misfire_policy,notnull,default:'FIRE_ONCE_NOW'"`
+	TimeoutSeconds    *int64                   `bun:"timeout_seconds"`           // nullable, runs are only limited by their executor
+	RetryPolicy       *RetryPolicy             `bun:"retry_policy,type:jsonb"`   // nullable, failed runs are not retried
+	Attempt           int                      `bun:"attempt,notnull,default:1"` // attempt of the current occurrence
+	Owner             *string                  `bun:"owner"`                     // instance executing the job while it is RUNNING
+	LeaseExpiresAt    *time.Time               `bun:"lease_expires_at"`          // renewed by the owner, the job is reaped once it passed
+	Attributes        map[string]interface{}   `bun:"attributes,type:jsonb"`     // explicitly specify JSONB type
+	CreatedBy         string                   `bun:"created_by,notnull"`
+	CreatedAt         time.Time                `bun:"created_at,notnull,default:current_timestamp"`
+	UpdatedAt         time.Time                `bun:"updated_at,notnull,default:current_timestamp"`
 }
 
 type GetJobByIDInput struct {
@@ -30,34 +44,160 @@ type GetJobByIDInput struct {
 }
 
 type CreateJobInput struct {
-	Name         string                 `json:"name" validate:"required,min=3,max=100" doc:"Job name"`
-	Description  *string                `json:"description,omitempty" validate:"omitempty,max=500" doc:"Job description"`
-	Interval     bool                   `json:"interval" validate:"-" doc:"Indicates if the job is recurring (default: false)" example:"false"`
-	IntervalTime *int64                 `json:"interval_time,omitempty" doc:"Interval time in minutes (for recurring jobs)" example:"1440 mins for a day"`
-	ScheduledAt  int64                  `json:"scheduled_at" validate:"required,gt=0" doc:"Scheduled time of the Job (Unix timestamp, must be in the future)" example:"1728691200"` // Unix timestamp
-	Attributes   map[string]interface{} `json:"attributes,omitempty" validate:"-" doc:"Custom job attributes (flexible key-value pairs)" example:"{\"priority\":\"high\",\"department\":\"engineering\",\"tags\":[\"critical\",\"backend\"]}"`
-	CreatedBy    string                 `json:"created_by" validate:"required,email" doc:"Email of the job creator"`
+	Body struct {
+		Name              string                   `json:"name" validate:"required,min=3,max=100" doc:"Job name"`
+		Description       *string                  `json:"description,omitempty" validate:"omitempty,max=500" doc:"Job description"`
+		Type              string                   `json:"type,omitempty" doc:"Executor running the job (noop, http, command), configured through the attribute named after the type (default: noop)" example:"http"`
+		Interval          bool                     `json:"interval" validate:"-" doc:"Indicates if the job is recurring (default: false)" example:"false"`
+		IntervalTime      *int64                   `json:"interval_time,omitempty" doc:"Interval time in minutes (for recurring jobs)" example:"1440"`
+		CronExpression    *string                  `json:"cron_expression,omitempty" doc:"Cron expression (for recurring jobs), 5 fields with an optional leading seconds field, or a macro like @daily. Cannot be combined with interval_time" example:"30 2 * * 1-5"`
+		Timezone          string                   `json:"timezone,omitempty" doc:"IANA timezone the cron expression or interval is evaluated in (default: UTC)" example:"Europe/Berlin"`
+		ScheduledAt       int64                    `json:"scheduled_at,omitempty" doc:"Scheduled time of the Job (Unix timestamp, must be in the future). Optional for cron jobs, defaults to the next occurrence of the expression" example:"1728691200"` // Unix timestamp
+		Attributes        map[string]interface{}   `json:"attributes,omitempty" validate:"-" doc:"Custom job attributes (flexible key-value pairs)" example:"{\"priority\":\"high\",\"department\":\"engineering\",\"tags\":[\"critical\",\"backend\"]}"`
+		TimeoutSeconds    *int64                   `json:"timeout_seconds,omitempty" minimum:"1" doc:"Maximum duration of a single execution in seconds, longer runs are cancelled and marked TIMED_OUT" example:"300"`
+		RetryPolicy       *RetryPolicy             `json:"retry_policy,omitempty" doc:"Retry policy for failed scheduled executions (default: no retries). A one-time job that failed all attempts becomes FAILED and is dead-lettered, a recurring job never becomes FAILED: it continues with its next occurrence and the failed attempts stay in its run history"`
+		DependsOn         []string                 `json:"depends_on,omitempty" doc:"Unique identifiers of upstream jobs, the job only runs once all of them succeeded in the same cycle"`
+		ConcurrencyPolicy shared.ConcurrencyPolicy `json:"concurrency_policy,omitempty" enum:"Allow,Forbid,Replace" doc:"What happens when a run starts while another run of the job is still running: Allow runs both, Forbid skips the new run, Replace cancels the running one (default: Allow)"`
+		Priority          int                      `json:"priority,omitempty" minimum:"0" maximum:"100" doc:"When several jobs are due, jobs with a higher priority run first (default: 0)" example:"10"`
+		MisfirePolicy     shared.MisfirePolicy     `json:"misfire_policy,omitempty" enum:"FIRE_ONCE_NOW,FIRE_ALL_MISSED,SKIP_TO_NEXT" doc:"What happens when the scheduled time passed while the scheduler was down: run once now, run every missed occurrence, or skip to the next future occurrence (default: FIRE_ONCE_NOW)"`
+		CreatedBy         string                   `json:"created_by" validate:"required,email" doc:"Email of the job creator"`
+	}
+}
+
+// UpdateJobInput carries a partial update, only the fields present in the body are changed.
+type UpdateJobInput struct {
+	ID   string `path:"id" validate:"required" doc:"Unique identifier of the job to update"`
+	Body struct {
+		Name              *string                   `json:"name,omitempty" validate:"omitempty,min=3,max=100" doc:"New job name"`
+		Description       *string                   `json:"description,omitempty" validate:"omitempty,max=500" doc:"New job description"`
+		Type              *string                   `json:"type,omitempty" doc:"New executor type of the job"`
+		IntervalTime      *int64                    `json:"interval_time,omitempty" minimum:"1" doc:"New interval time in minutes, replaces a cron expression" example:"1440"`
+		CronExpression    *string                   `json:"cron_expression,omitempty" doc:"New cron expression, replaces an interval time" example:"@daily"`
+		Timezone          *string                   `json:"timezone,omitempty" doc:"New IANA timezone for the schedule" example:"Asia/Kolkata"`
+		ScheduledAt       *int64                    `json:"scheduled_at,omitempty" doc:"New scheduled time of the job (Unix timestamp, must be in the future)" example:"1728691200"`
+		Attributes        map[string]interface{}    `json:"attributes,omitempty" doc:"Replaces the custom job attributes"`
+		TimeoutSeconds    *int64                    `json:"timeout_seconds,omitempty" minimum:"1" doc:"New maximum duration of a single execution in seconds" example:"300"`
+		RetryPolicy       *RetryPolicy              `json:"retry_policy,omitempty" doc:"Replaces the retry policy, max_attempts 1 disables retries"`
+		DependsOn         []string                  `json:"depends_on,omitempty" doc:"Replaces the upstream jobs, an empty list removes all dependencies"`
+		ConcurrencyPolicy *shared.ConcurrencyPolicy `json:"concurrency_policy,omitempty" enum:"Allow,Forbid,Replace" doc:"New concurrency policy of the job"`
+		Priority          *int                      `json:"priority,omitempty" minimum:"0" maximum:"100" doc:"New priority of the job"`
+		MisfirePolicy     *shared.MisfirePolicy     `json:"misfire_policy,omitempty" enum:"FIRE_ONCE_NOW,FIRE_ALL_MISSED,SKIP_TO_NEXT" doc:"New misfire policy of the job"`
+		Clear             []string                  `json:"clear,omitempty" enum:"description,interval_time,cron_expression,timeout_seconds,retry_policy" doc:"Fields to reset to null, clearing the interval time or cron expression turns a recurring job into a one-time job"`
+	}
+}
+
+type FilterJobsInput struct {
+	Status        []string `query:"status" enum:"SCHEDULED,RUNNING,COMPLETED,FAILED,PAUSED" doc:"Only return jobs in one of these statuses (comma separated)"`
+	CreatedBy     string   `query:"created_by" doc:"Only return jobs created by this email"`
+	ScheduledFrom int64    `query:"scheduled_from" doc:"Only return jobs scheduled at or after this time (Unix timestamp)"`
+	ScheduledTo   int64    `query:"scheduled_to" doc:"Only return jobs scheduled at or before this time (Unix timestamp)"`
+	NamePrefix    string   `query:"name_prefix" doc:"Only return jobs whose name starts with this prefix"`
+	Attributes    string   `query:"attributes" doc:"Only return jobs whose attributes contain this JSON object" example:"{\"department\":\"engineering\"}"`
+	Sort          string   `query:"sort" enum:"id,-id,scheduled_at,-scheduled_at,created_at,-created_at" default:"id" doc:"Sort field, prefix with - for descending order"`
+	Limit         int      `query:"limit" minimum:"1" maximum:"500" default:"50" doc:"Maximum number of jobs to return"`
+	Cursor        string   `query:"cursor" doc:"Opaque cursor from next_cursor of the previous page"`
 }
 
-type FilterJobsInput struct{}
+type PauseJobInput struct {
+	ID string `path:"id" validate:"required" doc:"Unique identifier of the job to pause"`
+}
+
+type ResumeJobInput struct {
+	ID string `path:"id" validate:"required" doc:"Unique identifier of the job to resume"`
+}
+
+type RunJobInput struct {
+	ID string `path:"id" validate:"required" doc:"Unique identifier of the job to run"`
+}
+
+type BackfillJobInput struct {
+	ID   string `path:"id" validate:"required" doc:"Unique identifier of the recurring job to backfill"`
+	Body struct {
+		From int64 `json:"from" doc:"Start of the range, inclusive (Unix timestamp)" example:"1728172800"`
+		To   int64 `json:"to" doc:"End of the range, inclusive (Unix timestamp, must not be in the future)" example:"1728604800"`
+	}
+}
+
+type FilterDeadLetterJobsInput struct {
+	Limit  int    `query:"limit" minimum:"1" maximum:"500" default:"50" doc:"Maximum number of jobs to return"`
+	Cursor string `query:"cursor" doc:"Opaque cursor from next_cursor of the previous page"`
+}
+
+type ReplayDeadLetterJobsInput struct {
+	Body struct {
+		JobIDs      []string `json:"job_ids" minItems:"1" maxItems:"100" doc:"Unique identifiers of the dead-lettered jobs to replay"`
+		ScheduledAt int64    `json:"scheduled_at,omitempty" doc:"Time the replayed jobs run at (Unix timestamp, must not be in the past), defaults to now" example:"1728691200"`
+	}
+}
+
+type GetDependencyGraphInput struct {
+	JobID string `query:"job_id" doc:"Only return the jobs connected to this job through dependencies"`
+}
 
 type DeleteJobByIDInput struct {
 	ID string `path:"id" validate:"required,uuid" doc:"Unique identifier of the job to delete"`
 }
 
 type JobDTO struct {
-	ID             string                 `json:"id" doc:"Unique identifier of the created job"`
-	Name           string                 `json:"name" doc:"Name of the created job"`
-	Description    *string                `json:"description,omitempty" doc:"Description of the created job"`
-	Status         shared.JobStatus       `json:"job_status" doc:"Current status of the job" enum:"PENDING,RUNNING,COMPLETED,FAILED"`
-	IntervalTime   int64                  `json:"interval_time" doc:"Interval time in minutes (for recurring jobs)" example:"1440 mins for a day"`
-	ScheduledAt    int64                  `json:"scheduled_at" doc:"Scheduled time of the job (Unix timestamp)"`
-	LastRunAt      *time.Time             `json:"last_run_at,omitempty" doc:"Last run time of the job"`
-	Attributes     map[string]interface{} `json:"attributes,omitempty" doc:"Custom job attributes"`
-	SuccessfulRuns int                    `json:"successful_runs" doc:"Number of successful runs for the job"`
-	CreatedBy      string                 `json:"created_by" doc:"Email of the job creator"`
-	CreatedAt      time.Time              `json:"created_at" doc:"Creation time of the job (Unix timestamp)"`
-	UpdatedAt      time.Time              `json:"updated_at" doc:"Last update time of the job (Unix timestamp)"`
+	ID                string                   `json:"id" doc:"Unique identifier of the created job"`
+	Name              string                   `json:"name" doc:"Name of the created job"`
+	Description       *string                  `json:"description,omitempty" doc:"Description of the created job"`
+	Status            shared.JobStatus         `json:"job_status" doc:"Current status of the job" enum:"SCHEDULED,RUNNING,COMPLETED,FAILED,PAUSED"`
+	Type              string                   `json:"type" doc:"Executor running the job"`
+	IntervalTime      *int64                   `json:"interval_time,omitempty" doc:"Interval time in minutes (for recurring jobs)" example:"1440"`
+	CronExpression    *string                  `json:"cron_expression,omitempty" doc:"Cron expression (for recurring jobs)" example:"30 2 * * 1-5"`
+	Timezone          string                   `json:"timezone" doc:"IANA timezone of the schedule" example:"Europe/Berlin"`
+	ScheduledAt       int64                    `json:"scheduled_at" doc:"Scheduled time of the job (Unix timestamp)"`
+	NextRunAt         *int64                   `json:"next_run_at,omitempty" doc:"Next occurrence of the job (Unix timestamp), only set while the job is scheduled"`
+	NextRunAtLocal    *string                  `json:"next_run_at_local,omitempty" doc:"Next occurrence of the job as RFC3339 in the job's timezone" example:"2024-10-12T09:00:00+05:30"`
+	LastRunAt         *time.Time               `json:"last_run_at,omitempty" doc:"Last run time of the job"`
+	Attributes        map[string]interface{}   `json:"attributes,omitempty" doc:"Custom job attributes"`
+	SuccessfulRuns    int                      `json:"successful_runs" doc:"Number of successful runs for the job"`
+	TimeoutSeconds    *int64                   `json:"timeout_seconds,omitempty" doc:"Maximum duration of a single execution in seconds"`
+	RetryPolicy       *RetryPolicy             `json:"retry_policy,omitempty" doc:"Retry policy for failed scheduled executions"`
+	Attempt           int                      `json:"attempt" doc:"Attempt the next execution of the current occurrence will be"`
+	OccurrenceAt      *int64                   `json:"occurrence_at,omitempty" doc:"Occurrence retried at scheduled_at (Unix timestamp), only set while a failed occurrence is retried"`
+	DependsOn         []string                 `json:"depends_on,omitempty" doc:"Unique identifiers of the upstream jobs"`
+	ConcurrencyPolicy shared.ConcurrencyPolicy `json:"concurrency_policy" doc:"What happens when a run starts while another run of the job is still running" enum:"Allow,Forbid,Replace"`
+	Priority          int                      `json:"priority" doc:"Jobs with a higher priority run first when several jobs are due"`
+	MisfirePolicy     shared.MisfirePolicy     `json:"misfire_policy" doc:"What happens when the scheduled time passed while the scheduler was down" enum:"FIRE_ONCE_NOW,FIRE_ALL_MISSED,SKIP_TO_NEXT"`
+	LastSucceededAt   *time.Time               `json:"last_succeeded_at,omitempty" doc:"End of the last successful scheduled run"`
+	Owner             *string                  `json:"owner,omitempty" doc:"Scheduler instance executing the job, only set while it is running"`
+	LeaseExpiresAt    *time.Time               `json:"lease_expires_at,omitempty" doc:"Time the owner's lease on the running job expires unless it is renewed"`
+	CreatedBy         string                   `json:"created_by" doc:"Email of the job creator"`
+	CreatedAt         time.Time                `json:"created_at" doc:"Creation time of the job (Unix timestamp)"`
+	UpdatedAt         time.Time                `json:"updated_at" doc:"Last update time of the job (Unix timestamp)"`
+}
+
+// BackfillRunDTO is the handle of a queued backfill run
+type BackfillRunDTO struct {
+	RunID        string `json:"run_id" doc:"Handle of the run, look it up with GET /jobs/{id}/runs/{runId}"`
+	ScheduledFor int64  `json:"scheduled_for" doc:"Logical execution time of the run (Unix timestamp)"`
+}
+
+// DeadLetterJobDTO is a permanently failed job together with the reason it failed
+type DeadLetterJobDTO struct {
+	Job           JobDTO               `json:"job" doc:"The failed job"`
+	FailureReason shared.FailureReason `json:"failure_reason" doc:"Why the job failed" enum:"EXECUTION_FAILED,TIMED_OUT,RETRIES_EXHAUSTED,MISFIRED"`
+	Attempts      int                  `json:"attempts" doc:"Number of attempts of the failed occurrence"`
+	LastError     *string              `json:"last_error,omitempty" doc:"Error message of the last failed run"`
+	LastRunID     *string              `json:"last_run_id,omitempty" doc:"Unique identifier of the last failed run"`
+	FailedAt      *time.Time           `json:"failed_at,omitempty" doc:"Time the last run failed"`
+}
+
+// DependencyNodeDTO is a job in the dependency graph
+type DependencyNodeDTO struct {
+	ID              string           `json:"id" doc:"Unique identifier of the job"`
+	Name            string           `json:"name" doc:"Name of the job"`
+	Status          shared.JobStatus `json:"job_status" doc:"Current status of the job" enum:"SCHEDULED,RUNNING,COMPLETED,FAILED,PAUSED"`
+	ScheduledAt     int64            `json:"scheduled_at" doc:"Scheduled time of the job (Unix timestamp)"`
+	LastSucceededAt *time.Time       `json:"last_succeeded_at,omitempty" doc:"End of the last successful scheduled run"`
+}
+
+// DependencyEdgeDTO states that the downstream job waits for the upstream job
+type DependencyEdgeDTO struct {
+	Upstream   string `json:"upstream" doc:"Unique identifier of the job that must succeed first"`
+	Downstream string `json:"downstream" doc:"Unique identifier of the job waiting for it"`
 }
 
 // Huma response wrappers
@@ -66,13 +206,61 @@ type CreateJobResponse struct {
 	Body JobDTO
 }
 
+type UpdateJobResponse struct {
+	Body JobDTO
+}
+
 type GetJobByIDResponse struct {
 	Body JobDTO
 }
 
 type FilterJobsResponse struct {
 	Body struct {
-		Jobs []JobDTO `json:"jobs" doc:"List of jobs"`
+		Jobs       []JobDTO `json:"jobs" doc:"List of jobs"`
+		NextCursor string   `json:"next_cursor,omitempty" doc:"Cursor for the next page, empty on the last page"`
+	}
+}
+
+type PauseJobResponse struct {
+	Body JobDTO
+}
+
+type ResumeJobResponse struct {
+	Body JobDTO
+}
+
+type RunJobResponse struct {
+	Body struct {
+		RunID       string `json:"run_id" doc:"Handle of the ad-hoc run, look it up with GET /jobs/{id}/runs/{runId}"`
+		JobID       string `json:"job_id" doc:"Unique identifier of the job"`
+		TriggeredAt int64  `json:"triggered_at" doc:"Time the run was requested (Unix timestamp)"`
+	}
+}
+
+type BackfillJobResponse struct {
+	Body struct {
+		JobID string           `json:"job_id" doc:"Unique identifier of the job"`
+		Runs  []BackfillRunDTO `json:"runs" doc:"Queued backfill runs, one per occurrence in the range"`
+	}
+}
+
+type FilterDeadLetterJobsResponse struct {
+	Body struct {
+		Jobs       []DeadLetterJobDTO `json:"jobs" doc:"List of dead-lettered jobs"`
+		NextCursor string             `json:"next_cursor,omitempty" doc:"Cursor for the next page, empty on the last page"`
+	}
+}
+
+type ReplayDeadLetterJobsResponse struct {
+	Body struct {
+		Jobs []JobDTO `json:"jobs" doc:"The replayed jobs, scheduled again"`
+	}
+}
+
+type GetDependencyGraphResponse struct {
+	Body struct {
+		Nodes []DependencyNodeDTO `json:"nodes" doc:"Jobs that depend on or are depended on by other jobs"`
+		Edges []DependencyEdgeDTO `json:"edges" doc:"Dependencies between the jobs"`
 	}
 }
 
@@ -0,0 +1,92 @@
+package jobrun
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/sdivyansh59/digantara-backend-golang-assignment/internal-lib/snowflake"
+	"github.com/sdivyansh59/digantara-backend-golang-assignment/internal-lib/utils"
+)
+
+type Controller struct {
+	*utils.WithLogger
+	converter  *Converter
+	repository IRepository
+}
+
+func NewController(logger *utils.WithLogger, converter *Converter, repository IRepository) *Controller {
+	return &Controller{
+		WithLogger: logger,
+		converter:  converter,
+		repository: repository,
+	}
+}
+
+func (c *Controller) FilterJobRuns(ctx context.Context, input *FilterJobRunsInput) (*FilterJobRunsResponse, error) {
+	if !c.isAuthorized(ctx) {
+		return nil, fmt.Errorf("unauthorized: you do not have permission to list runs of this job")
+	}
+
+	// validate job's id
+	jobID, err := snowflake.ConvertToSnowflake(input.ID)
+	if err != nil {
+		return nil, fmt.Errorf("invalid job ID: %w", err)
+	}
+
+	filter, err := input.filter(jobID)
+	if err != nil {
+		return nil, fmt.Errorf("invalid cursor: %w", err)
+	}
+
+	entities, err := c.repository.Filter(ctx, filter)
+	if err != nil {
+		return nil, fmt.Errorf("failed to filter job runs: %w", err)
+	}
+
+	resp := &FilterJobRunsResponse{}
+
+	// The extra run fetched beyond the page size means there is a next page
+	if len(entities) > input.pageSize() {
+		entities = entities[:input.pageSize()]
+		resp.Body.NextCursor, err = input.nextCursor(&entities[len(entities)-1])
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	runs := make([]JobRunDTO, 0, len(entities))
+	for _, entity := range entities {
+		runs = append(runs, *c.converter.ToDTO(&entity))
+	}
+
+	resp.Body.Runs = runs
+	return resp, nil
+}
+
+func (c *Controller) GetJobRunByID(ctx context.Context, input *GetJobRunByIDInput) (*GetJobRunByIDResponse, error) {
+	if !c.isAuthorized(ctx) {
+		return nil, fmt.Errorf("unauthorized: you do not have permission to view this run")
+	}
+
+	// validate ids
+	jobID, err := snowflake.ConvertToSnowflake(input.ID)
+	if err != nil {
+		return nil, fmt.Errorf("invalid job ID: %w", err)
+	}
+	runID, err := snowflake.ConvertToSnowflake(input.RunID)
+	if err != nil {
+		return nil, fmt.Errorf("invalid run ID: %w", err)
+	}
+
+	run, err := c.repository.GetByID(ctx, runID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to retrieve run: %w", err)
+	}
+	if run == nil || run.JobId != jobID {
+		return nil, fmt.Errorf("run not found")
+	}
+
+	return &GetJobRunByIDResponse{
+		Body: *c.converter.ToDTO(run),
+	}, nil
+}
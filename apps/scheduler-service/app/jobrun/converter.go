@@ -0,0 +1,36 @@
+package jobrun
+
+type Converter struct {
+}
+
+func NewConverter() *Converter {
+	return &Converter{}
+}
+
+func (c *Converter) ToDTO(entity *JobRun) *JobRunDTO {
+	if entity == nil {
+		return nil
+	}
+
+	dto := &JobRunDTO{
+		ID:           entity.Id.String(),
+		JobID:        entity.JobId.String(),
+		Status:       entity.Status,
+		Trigger:      entity.Trigger,
+		Attempt:      entity.Attempt,
+		ScheduledFor: entity.ScheduledFor,
+		StartedAt:    entity.StartedAt,
+		FinishedAt:   entity.FinishedAt,
+		ErrorMessage: entity.ErrorMessage,
+		Decision:     entity.Decision,
+		Result:       entity.Result,
+		CreatedAt:    entity.CreatedAt,
+	}
+
+	if entity.StartedAt != nil && entity.FinishedAt != nil {
+		duration := entity.FinishedAt.Sub(*entity.StartedAt).Milliseconds()
+		dto.DurationMs = &duration
+	}
+
+	return dto
+}
@@ -0,0 +1,89 @@
+package jobrun
+
+import (
+	"slices"
+
+	"github.com/sdivyansh59/digantara-backend-golang-assignment/app/shared"
+	"github.com/sdivyansh59/digantara-backend-golang-assignment/internal-lib/database/query"
+	"github.com/sdivyansh59/digantara-backend-golang-assignment/internal-lib/snowflake"
+)
+
+const defaultPageSize = 50
+
+// Filter selects the runs of a job in IRepository.Filter, newest first. Zero fields do not restrict the selection.
+type Filter struct {
+	JobID    snowflake.ID
+	Statuses []shared.RunStatus
+	Before   snowflake.ID // only runs older than this one, e.g. the last run of the previous page
+	Limit    int
+}
+
+// searchOptions translates the filter into the search options of the Postgres repository.
+func (f *Filter) searchOptions() []query.SearchOption {
+	options := []query.SearchOption{
+		query.Where("job_run.job_id", f.JobID),
+		query.OrderBy("job_run.id", "job_run.id", true),
+	}
+
+	if len(f.Statuses) > 0 {
+		options = append(options, query.WhereIn("job_run.status", f.Statuses))
+	}
+
+	if f.Before != 0 {
+		options = append(options, query.After("job_run.id", f.Before, "job_run.id", f.Before, true))
+	}
+
+	if f.Limit > 0 {
+		options = append(options, query.Limit(f.Limit))
+	}
+
+	return options
+}
+
+// matches tells whether run passes the conditions of the filter, like searchOptions does in Postgres.
+func (f *Filter) matches(run *JobRun) bool {
+	return run.JobId == f.JobID &&
+		(len(f.Statuses) == 0 || slices.Contains(f.Statuses, run.Status)) &&
+		(f.Before == 0 || run.Id < f.Before)
+}
+
+// runCursor is the position of the last run of a page, encoded into the opaque next_cursor.
+type runCursor struct {
+	ID snowflake.ID `json:"id"`
+}
+
+// filter returns the repository filter for the requested page of a job's runs.
+// One extra run is fetched to find out whether another page follows.
+func (input *FilterJobRunsInput) filter(jobID snowflake.ID) (*Filter, error) {
+	filter := &Filter{
+		JobID: jobID,
+		Limit: input.pageSize() + 1,
+	}
+
+	for _, status := range input.Status {
+		filter.Statuses = append(filter.Statuses, shared.RunStatus(status))
+	}
+
+	if input.Cursor != "" {
+		var cursor runCursor
+		if err := query.DecodeCursor(input.Cursor, &cursor); err != nil {
+			return nil, err
+		}
+		filter.Before = cursor.ID
+	}
+
+	return filter, nil
+}
+
+// nextCursor returns the cursor pointing after the last run of the page.
+func (input *FilterJobRunsInput) nextCursor(last *JobRun) (string, error) {
+	return query.EncodeCursor(runCursor{ID: last.Id})
+}
+
+func (input *FilterJobRunsInput) pageSize() int {
+	if input.Limit <= 0 {
+		return defaultPageSize
+	}
+
+	return input.Limit
+}
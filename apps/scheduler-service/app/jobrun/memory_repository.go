@@ -0,0 +1,313 @@
+package jobrun
+
+import (
+	"cmp"
+	"context"
+	"fmt"
+	"slices"
+	"sync"
+	"time"
+
+	"github.com/sdivyansh59/digantara-backend-golang-assignment/app/shared"
+	"github.com/sdivyansh59/digantara-backend-golang-assignment/internal-lib/database"
+	"github.com/sdivyansh59/digantara-backend-golang-assignment/internal-lib/snowflake"
+	"github.com/sdivyansh59/digantara-backend-golang-assignment/internal-lib/utils"
+)
+
+// MemoryRepository keeps the run history in memory instead of Postgres, next to the in-memory job repository.
+// It is safe for concurrent use and hands out copies.
+type MemoryRepository struct {
+	mu                 sync.Mutex
+	snowflakeGenerator *snowflake.Generator
+	runs               map[snowflake.ID]*JobRun
+}
+
+func NewMemoryRepository(snowflakeGenerator *snowflake.Generator) *MemoryRepository {
+	return &MemoryRepository{
+		snowflakeGenerator: snowflakeGenerator,
+		runs:               make(map[snowflake.ID]*JobRun),
+	}
+}
+
+// Filter returns the runs selected by filter, newest first.
+func (r *MemoryRepository) Filter(_ context.Context, filter *Filter) ([]JobRun, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	var runs []JobRun
+	for _, run := range r.runs {
+		if !filter.matches(run) {
+			continue
+		}
+
+		copied, err := clone(run)
+		if err != nil {
+			return nil, err
+		}
+		runs = append(runs, copied)
+	}
+	slices.SortFunc(runs, func(a, b JobRun) int { return cmp.Compare(b.Id, a.Id) })
+
+	if filter.Limit > 0 {
+		runs = runs[:min(filter.Limit, len(runs))]
+	}
+
+	return runs, nil
+}
+
+// Create stores a run, keeping the id if the caller already handed one out (e.g. for ad-hoc runs).
+func (r *MemoryRepository) Create(_ context.Context, run *JobRun) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	if run.Id == 0 {
+		run.Id = r.snowflakeGenerator.Next()
+	}
+	run.CreatedAt = time.Now()
+	run.UpdatedAt = time.Now()
+
+	stored, err := clone(run)
+	if err != nil {
+		return err
+	}
+	r.runs[run.Id] = &stored
+
+	return nil
+}
+
+func (r *MemoryRepository) Update(_ context.Context, run *JobRun) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	if _, ok := r.runs[run.Id]; !ok {
+		return nil
+	}
+
+	run.UpdatedAt = time.Now()
+	stored, err := clone(run)
+	if err != nil {
+		return err
+	}
+	r.runs[run.Id] = &stored
+
+	return nil
+}
+
+func (r *MemoryRepository) GetByID(_ context.Context, id snowflake.ID) (*JobRun, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	stored, ok := r.runs[id]
+	if !ok {
+		return nil, database.ErrNotFound
+	}
+
+	run, err := clone(stored)
+	if err != nil {
+		return nil, err
+	}
+
+	return &run, nil
+}
+
+// GetLatestByJobIDs returns the most recent run with the given trigger of each job, keyed by job id.
+// Jobs without such a run are missing from the map.
+func (r *MemoryRepository) GetLatestByJobIDs(_ context.Context, jobIDs []snowflake.ID, trigger shared.RunTrigger) (map[snowflake.ID]*JobRun, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	latest := make(map[snowflake.ID]*JobRun, len(jobIDs))
+	for _, run := range r.runs {
+		if run.Trigger != trigger || !slices.Contains(jobIDs, run.JobId) {
+			continue
+		}
+		if previous, ok := latest[run.JobId]; !ok || run.Id > previous.Id {
+			copied, err := clone(run)
+			if err != nil {
+				return nil, err
+			}
+			latest[run.JobId] = &copied
+		}
+	}
+
+	return latest, nil
+}
+
+// FailRunning marks the RUNNING runs of the job with the given trigger as FAILED.
+func (r *MemoryRepository) FailRunning(_ context.Context, jobID snowflake.ID, trigger shared.RunTrigger, message string) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	now := time.Now()
+	for _, run := range r.runs {
+		if run.JobId != jobID || run.Trigger != trigger || run.Status != shared.RunStatusRunning {
+			continue
+		}
+
+		run.Status = shared.RunStatusFailed
+		run.ErrorMessage = &message
+		run.FinishedAt = &now
+		run.UpdatedAt = now
+	}
+
+	return nil
+}
+
+// DeleteByJobID deletes the run history of a deleted job, like ON DELETE CASCADE does in Postgres.
+func (r *MemoryRepository) DeleteByJobID(_ context.Context, jobID snowflake.ID) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	for id, run := range r.runs {
+		if run.JobId == jobID {
+			delete(r.runs, id)
+		}
+	}
+
+	return nil
+}
+
+// GetQueued returns up to limit QUEUED runs, oldest first, leaving out the backfill runs of the jobs in
+// skipBackfillsOf.
+func (r *MemoryRepository) GetQueued(_ context.Context, limit int, skipBackfillsOf []snowflake.ID) ([]JobRun, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	var runs []JobRun
+	for _, run := range r.runs {
+		skipped := run.Trigger == shared.RunTriggerBackfill && slices.Contains(skipBackfillsOf, run.JobId)
+		if run.Status != shared.RunStatusQueued || skipped {
+			continue
+		}
+
+		copied, err := clone(run)
+		if err != nil {
+			return nil, err
+		}
+		runs = append(runs, copied)
+	}
+	slices.SortFunc(runs, func(a, b JobRun) int { return cmp.Compare(a.Id, b.Id) })
+
+	return runs[:min(limit, len(runs))], nil
+}
+
+// GetNextQueued returns the oldest QUEUED run of the job with the given trigger, or nil if there is none.
+func (r *MemoryRepository) GetNextQueued(_ context.Context, jobID snowflake.ID, trigger shared.RunTrigger) (*JobRun, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	var next *JobRun
+	for _, run := range r.runs {
+		if run.JobId == jobID && run.Status == shared.RunStatusQueued && run.Trigger == trigger &&
+			(next == nil || run.Id < next.Id) {
+			next = run
+		}
+	}
+	if next == nil {
+		return nil, nil
+	}
+
+	run, err := clone(next)
+	if err != nil {
+		return nil, err
+	}
+
+	return &run, nil
+}
+
+// ClaimQueued flips the QUEUED run to RUNNING, leased to owner for the given duration, so it is dispatched only once.
+// Returns false if the run is no longer queued.
+func (r *MemoryRepository) ClaimQueued(_ context.Context, run *JobRun, owner string, lease time.Duration) (bool, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	stored, ok := r.runs[run.Id]
+	if !ok || stored.Status != shared.RunStatusQueued {
+		return false, nil
+	}
+
+	now := time.Now()
+	leaseExpiresAt := now.Add(lease)
+	stored.Status = shared.RunStatusRunning
+	stored.Owner = &owner
+	stored.LeaseExpiresAt = &leaseExpiresAt
+	stored.UpdatedAt = now
+	run.Status = stored.Status
+	run.Owner = utils.CopyPointer(stored.Owner)
+	run.LeaseExpiresAt = utils.CopyPointer(stored.LeaseExpiresAt)
+	run.UpdatedAt = stored.UpdatedAt
+	return true, nil
+}
+
+// RenewLease extends the stored lease of the claimed run held by its owner, leaving run untouched.
+// Returns false if the run is no longer RUNNING and leased to the owner.
+func (r *MemoryRepository) RenewLease(_ context.Context, run *JobRun, lease time.Duration) (bool, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	stored, ok := r.runs[run.Id]
+	if !ok || stored.Status != shared.RunStatusRunning || stored.Owner == nil || run.Owner == nil ||
+		*stored.Owner != *run.Owner {
+		return false, nil
+	}
+
+	leaseExpiresAt := time.Now().Add(lease)
+	stored.LeaseExpiresAt = &leaseExpiresAt
+	return true, nil
+}
+
+// FailExpiredLeases marks up to limit claimed RUNNING runs whose lease expired as FAILED with the given message.
+// Returns the failed runs.
+func (r *MemoryRepository) FailExpiredLeases(_ context.Context, message string, limit int) ([]JobRun, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	now := time.Now()
+
+	var expired []*JobRun
+	for _, run := range r.runs {
+		if run.Status == shared.RunStatusRunning && run.LeaseExpiresAt != nil && run.LeaseExpiresAt.Before(now) {
+			expired = append(expired, run)
+		}
+	}
+	slices.SortFunc(expired, func(a, b *JobRun) int { return a.LeaseExpiresAt.Compare(*b.LeaseExpiresAt) })
+
+	runs := make([]JobRun, 0, min(limit, len(expired)))
+	for _, run := range expired[:min(limit, len(expired))] {
+		run.Status = shared.RunStatusFailed
+		run.ErrorMessage = &message
+		run.FinishedAt = &now
+		run.UpdatedAt = now
+
+		copied, err := clone(run)
+		if err != nil {
+			return nil, err
+		}
+		runs = append(runs, copied)
+	}
+
+	return runs, nil
+}
+
+// clone copies run along with the values its pointers and result refer to, so neither side sees the other's later
+// changes.
+// The result is deep-copied through JSON, which also stores it the way a jsonb column would.
+func clone(run *JobRun) (JobRun, error) {
+	copied := *run
+	copied.ScheduledFor = utils.CopyPointer(run.ScheduledFor)
+	copied.StartedAt = utils.CopyPointer(run.StartedAt)
+	copied.FinishedAt = utils.CopyPointer(run.FinishedAt)
+	copied.ErrorMessage = utils.CopyPointer(run.ErrorMessage)
+	copied.Decision = utils.CopyPointer(run.Decision)
+	copied.Owner = utils.CopyPointer(run.Owner)
+	copied.LeaseExpiresAt = utils.CopyPointer(run.LeaseExpiresAt)
+	copied.Result = nil
+
+	if run.Result != nil {
+		if err := utils.CopyJSON(run.Result, &copied.Result); err != nil {
+			return JobRun{}, fmt.Errorf("failed to encode result of run %s: %w", run.Id, err)
+		}
+	}
+
+	return copied, nil
+}
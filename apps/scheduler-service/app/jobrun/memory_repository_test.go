@@ -0,0 +1,64 @@
+package jobrun
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/sdivyansh59/digantara-backend-golang-assignment/app/shared"
+	"github.com/sdivyansh59/digantara-backend-golang-assignment/internal-lib/snowflake"
+	"github.com/stretchr/testify/require"
+)
+
+func TestMemoryRepository_RunLease(t *testing.T) {
+	ctx := context.Background()
+	generator, err := snowflake.NewGenerator(1)
+	require.NoError(t, err)
+	repository := NewMemoryRepository(generator)
+
+	run := &JobRun{JobId: generator.Next(), Status: shared.RunStatusQueued, Trigger: shared.RunTriggerManual}
+	require.NoError(t, repository.Create(ctx, run))
+
+	// Claimed with a lease that already expired, as if the instance died
+	claimed, err := repository.ClaimQueued(ctx, run, "instance-1", -time.Second)
+	require.NoError(t, err)
+	require.True(t, claimed)
+	require.Equal(t, "instance-1", *run.Owner)
+
+	claimed, err = repository.ClaimQueued(ctx, run, "instance-2", time.Minute)
+	require.NoError(t, err)
+	require.False(t, claimed)
+
+	failed, err := repository.FailExpiredLeases(ctx, "lease expired", 10)
+	require.NoError(t, err)
+	require.Len(t, failed, 1)
+	require.Equal(t, run.Id, failed[0].Id)
+	require.Equal(t, shared.RunStatusFailed, failed[0].Status)
+
+	// The owner finds out through the renewal
+	renewed, err := repository.RenewLease(ctx, run, time.Minute)
+	require.NoError(t, err)
+	require.False(t, renewed)
+}
+
+func TestMemoryRepository_DeleteByJobID(t *testing.T) {
+	ctx := context.Background()
+	generator, err := snowflake.NewGenerator(1)
+	require.NoError(t, err)
+	repository := NewMemoryRepository(generator)
+
+	deleted, kept := generator.Next(), generator.Next()
+	for _, jobID := range []snowflake.ID{deleted, deleted, kept} {
+		require.NoError(t, repository.Create(ctx, &JobRun{JobId: jobID, Status: shared.RunStatusSucceeded}))
+	}
+
+	require.NoError(t, repository.DeleteByJobID(ctx, deleted))
+
+	runs, err := repository.Filter(ctx, &Filter{JobID: deleted})
+	require.NoError(t, err)
+	require.Empty(t, runs)
+
+	runs, err = repository.Filter(ctx, &Filter{JobID: kept})
+	require.NoError(t, err)
+	require.Len(t, runs, 1)
+}
@@ -0,0 +1,246 @@
+package jobrun
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"time"
+
+	"github.com/sdivyansh59/digantara-backend-golang-assignment/app/setup/dbconfig"
+	"github.com/sdivyansh59/digantara-backend-golang-assignment/app/shared"
+	"github.com/sdivyansh59/digantara-backend-golang-assignment/internal-lib/database"
+	"github.com/sdivyansh59/digantara-backend-golang-assignment/internal-lib/database/crud"
+	"github.com/sdivyansh59/digantara-backend-golang-assignment/internal-lib/database/query"
+	"github.com/sdivyansh59/digantara-backend-golang-assignment/internal-lib/snowflake"
+	"github.com/uptrace/bun"
+)
+
+type IRepository interface {
+	Filter(ctx context.Context, filter *Filter) ([]JobRun, error)
+	Create(ctx context.Context, run *JobRun) error
+	Update(ctx context.Context, run *JobRun) error
+	GetByID(ctx context.Context, id snowflake.ID) (*JobRun, error)
+	GetLatestByJobIDs(ctx context.Context, jobIDs []snowflake.ID, trigger shared.RunTrigger) (map[snowflake.ID]*JobRun, error)
+	FailRunning(ctx context.Context, jobID snowflake.ID, trigger shared.RunTrigger, message string) error
+	DeleteByJobID(ctx context.Context, jobID snowflake.ID) error
+	GetQueued(ctx context.Context, limit int, skipBackfillsOf []snowflake.ID) ([]JobRun, error)
+	GetNextQueued(ctx context.Context, jobID snowflake.ID, trigger shared.RunTrigger) (*JobRun, error)
+	ClaimQueued(ctx context.Context, run *JobRun, owner string, lease time.Duration) (bool, error)
+	RenewLease(ctx context.Context, run *JobRun, lease time.Duration) (bool, error)
+	FailExpiredLeases(ctx context.Context, message string, limit int) ([]JobRun, error)
+}
+
+type Repository struct {
+	db                 *bun.DB
+	snowflakeGenerator *snowflake.Generator
+	handler            *crud.Handler[JobRun, snowflake.ID]
+}
+
+// NewRepository returns the Postgres repository, or the in-memory one if storage is memory.
+func NewRepository(snowflakeGenerator *snowflake.Generator, jobSchedulerDB *dbconfig.JobSchedulerDB, storage dbconfig.Storage) IRepository {
+	if storage == dbconfig.StorageMemory {
+		return NewMemoryRepository(snowflakeGenerator)
+	}
+
+	return &Repository{
+		db:                 jobSchedulerDB.DB,
+		snowflakeGenerator: snowflakeGenerator,
+		handler:            crud.NewHandler[JobRun, snowflake.ID](jobSchedulerDB.DB),
+	}
+}
+
+func (r *Repository) Filter(ctx context.Context, filter *Filter) ([]JobRun, error) {
+	return r.handler.Search(ctx, filter.searchOptions()...)
+}
+
+// Create inserts a run, keeping the id if the caller already handed one out (e.g. for ad-hoc runs).
+func (r *Repository) Create(ctx context.Context, run *JobRun) error {
+	if run.Id == 0 {
+		run.Id = r.snowflakeGenerator.Next()
+	}
+	run.CreatedAt = time.Now()
+	run.UpdatedAt = time.Now()
+
+	return r.handler.Create(ctx, run)
+}
+
+func (r *Repository) Update(ctx context.Context, run *JobRun) error {
+	run.UpdatedAt = time.Now()
+	return r.handler.Update(ctx, run)
+}
+
+func (r *Repository) GetByID(ctx context.Context, id snowflake.ID) (*JobRun, error) {
+	return r.handler.GetByID(ctx, id)
+}
+
+// GetLatestByJobIDs returns the most recent run with the given trigger of each job, keyed by job id.
+// Jobs without such a run are missing from the map.
+func (r *Repository) GetLatestByJobIDs(ctx context.Context, jobIDs []snowflake.ID, trigger shared.RunTrigger) (map[snowflake.ID]*JobRun, error) {
+	latest := make(map[snowflake.ID]*JobRun, len(jobIDs))
+	if len(jobIDs) == 0 {
+		return latest, nil
+	}
+
+	runs, err := r.handler.Search(ctx,
+		query.WhereIn("job_run.job_id", jobIDs),
+		query.Where("job_run.trigger_type", trigger),
+		func(q *bun.SelectQuery) *bun.SelectQuery {
+			return q.DistinctOn("job_run.job_id").OrderExpr("job_run.job_id, job_run.id DESC")
+		},
+	)
+	if err != nil {
+		return nil, err
+	}
+
+	for i := range runs {
+		latest[runs[i].JobId] = &runs[i]
+	}
+
+	return latest, nil
+}
+
+// FailRunning marks the RUNNING runs of the job with the given trigger as FAILED, e.g. after the instance executing
+// them died.
+func (r *Repository) FailRunning(ctx context.Context, jobID snowflake.ID, trigger shared.RunTrigger, message string) error {
+	now := time.Now()
+
+	_, err := database.GetIDBFromContext(ctx, r.db).
+		NewUpdate().
+		Model((*JobRun)(nil)).
+		Set("status = ?", shared.RunStatusFailed).
+		Set("error_message = ?", message).
+		Set("finished_at = ?", now).
+		Set("updated_at = ?", now).
+		Where("job_id = ?", jobID).
+		Where("trigger_type = ?", trigger).
+		Where("status = ?", shared.RunStatusRunning).
+		Exec(ctx)
+
+	return err
+}
+
+// DeleteByJobID deletes the run history of a deleted job. Postgres removes it along with the job through
+// ON DELETE CASCADE, so nothing is left to delete.
+func (r *Repository) DeleteByJobID(_ context.Context, _ snowflake.ID) error {
+	return nil
+}
+
+// GetQueued returns up to limit QUEUED runs, oldest first, leaving out the backfill runs of the jobs in
+// skipBackfillsOf.
+func (r *Repository) GetQueued(ctx context.Context, limit int, skipBackfillsOf []snowflake.ID) ([]JobRun, error) {
+	options := []query.SearchOption{
+		query.Where("job_run.status", shared.RunStatusQueued),
+		query.OrderBy("job_run.id", "job_run.id", false),
+		query.Limit(limit),
+	}
+	if len(skipBackfillsOf) > 0 {
+		options = append(options, func(q *bun.SelectQuery) *bun.SelectQuery {
+			return q.Where("NOT (job_run.trigger_type = ? AND job_run.job_id IN (?))", shared.RunTriggerBackfill, bun.In(skipBackfillsOf))
+		})
+	}
+
+	return r.handler.Search(ctx, options...)
+}
+
+// GetNextQueued returns the oldest QUEUED run of the job with the given trigger, or nil if there is none.
+func (r *Repository) GetNextQueued(ctx context.Context, jobID snowflake.ID, trigger shared.RunTrigger) (*JobRun, error) {
+	runs, err := r.handler.Search(ctx,
+		query.Where("job_run.job_id", jobID),
+		query.Where("job_run.status", shared.RunStatusQueued),
+		query.Where("job_run.trigger_type", trigger),
+		query.OrderBy("job_run.id", "job_run.id", false),
+		query.Limit(1),
+	)
+	if err != nil || len(runs) == 0 {
+		return nil, err
+	}
+
+	return &runs[0], nil
+}
+
+// ClaimQueued flips the QUEUED run to RUNNING, leased to owner for the given duration, so it is dispatched only once.
+// Returns false if the run is no longer queued, e.g. because another scheduler instance claimed it.
+func (r *Repository) ClaimQueued(ctx context.Context, run *JobRun, owner string, lease time.Duration) (bool, error) {
+	now := time.Now()
+	leaseExpiresAt := now.Add(lease)
+
+	result, err := database.GetIDBFromContext(ctx, r.db).
+		NewUpdate().
+		Model((*JobRun)(nil)).
+		Set("status = ?", shared.RunStatusRunning).
+		Set("owner = ?", owner).
+		Set("lease_expires_at = ?", leaseExpiresAt).
+		Set("updated_at = ?", now).
+		Where("id = ?", run.Id).
+		Where("status = ?", shared.RunStatusQueued).
+		Exec(ctx)
+	if err != nil {
+		return false, err
+	}
+
+	claimed, err := result.RowsAffected()
+	if err != nil {
+		return false, err
+	}
+	if claimed == 0 {
+		return false, nil
+	}
+
+	run.Status = shared.RunStatusRunning
+	run.Owner = &owner
+	run.LeaseExpiresAt = &leaseExpiresAt
+	run.UpdatedAt = now
+	return true, nil
+}
+
+// RenewLease extends the lease of the claimed run held by its owner. Only the stored lease is extended, the run is
+// left untouched as its executor may be recording it at the same time. Returns false if the run is no longer
+// RUNNING and leased to the owner, e.g. because the reaper failed it.
+func (r *Repository) RenewLease(ctx context.Context, run *JobRun, lease time.Duration) (bool, error) {
+	result, err := database.GetIDBFromContext(ctx, r.db).
+		NewUpdate().
+		Model((*JobRun)(nil)).
+		Set("lease_expires_at = ?", time.Now().Add(lease)).
+		Where("id = ?", run.Id).
+		Where("owner = ?", run.Owner).
+		Where("status = ?", shared.RunStatusRunning).
+		Exec(ctx)
+	if err != nil {
+		return false, err
+	}
+
+	renewed, err := result.RowsAffected()
+	if err != nil {
+		return false, err
+	}
+
+	return renewed > 0, nil
+}
+
+// FailExpiredLeases marks up to limit claimed RUNNING runs whose lease expired as FAILED with the given message,
+// e.g. because the instance executing them died. Returns the failed runs.
+func (r *Repository) FailExpiredLeases(ctx context.Context, message string, limit int) ([]JobRun, error) {
+	failExpired := `
+  UPDATE job_run
+  SET status = ?, error_message = ?, finished_at = ?, updated_at = ?
+  WHERE id IN (
+   SELECT id FROM job_run
+   WHERE status = ? AND lease_expires_at < ?
+   ORDER BY lease_expires_at ASC
+   LIMIT ?
+   FOR UPDATE SKIP LOCKED
+  )
+  RETURNING *`
+
+	now := time.Now()
+
+	var runs []JobRun
+	err := database.GetIDBFromContext(ctx, r.db).
+		NewRaw(failExpired, shared.RunStatusFailed, message, now, now, shared.RunStatusRunning, now, limit).
+		Scan(ctx, &runs)
+	if err != nil && !errors.Is(err, sql.ErrNoRows) {
+		return nil, err
+	}
+
+	return runs, nil
+}
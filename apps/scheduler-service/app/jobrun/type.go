@@ -0,0 +1,71 @@
+package jobrun
+
+import (
+	"time"
+
+	"github.com/sdivyansh59/digantara-backend-golang-assignment/app/shared"
+	"github.com/sdivyansh59/digantara-backend-golang-assignment/internal-lib/snowflake"
+	"github.com/uptrace/bun"
+)
+
+// JobRun is a single execution of a job
+type JobRun struct {
+	bun.BaseModel `bun:"table:job_run,alias:job_run"`
+
+	Id             snowflake.ID                `bun:"id,pk,notnull"`
+	JobId          snowflake.ID                `bun:"job_id,notnull"`
+	Status         shared.RunStatus            `bun:"status,notnull"`
+	Trigger        shared.RunTrigger           `bun:"trigger_type,notnull"`
+	Attempt        int                         `bun:"attempt,notnull,default:1"`
+	ScheduledFor   *int64                      `bun:"scheduled_for"` // logical time of the occurrence (Unix timestamp), nil for ad-hoc runs
+	StartedAt      *time.Time                  `bun:"started_at"`
+	FinishedAt     *time.Time                  `bun:"finished_at"`
+	ErrorMessage   *string                     `bun:"error_message"`
+	Decision       *shared.ConcurrencyDecision `bun:"concurrency_decision"` // set if the run overlapped with another one
+	Result         map[string]interface{}      `bun:"result,type:jsonb"`    // output reported by the executor
+	Owner          *string                     `bun:"owner"`                // instance executing a claimed queued run
+	LeaseExpiresAt *time.Time                  `bun:"lease_expires_at"`     // renewed by the owner, the run is failed once it passed
+	CreatedAt      time.Time                   `bun:"created_at,notnull,default:current_timestamp"`
+	UpdatedAt      time.Time                   `bun:"updated_at,notnull,default:current_timestamp"`
+}
+
+type GetJobRunByIDInput struct {
+	ID    string `path:"id" validate:"required" doc:"Unique identifier of the job"`
+	RunID string `path:"runId" validate:"required" doc:"Unique identifier of the run"`
+}
+
+type FilterJobRunsInput struct {
+	ID     string   `path:"id" validate:"required" doc:"Unique identifier of the job"`
+	Status []string `query:"status" enum:"QUEUED,RUNNING,SUCCEEDED,FAILED,TIMED_OUT,SKIPPED,CANCELLED,MISFIRED" doc:"Only return runs in one of these statuses (comma separated)"`
+	Limit  int      `query:"limit" minimum:"1" maximum:"500" default:"50" doc:"Maximum number of runs to return"`
+	Cursor string   `query:"cursor" doc:"Opaque cursor from next_cursor of the previous page"`
+}
+
+type JobRunDTO struct {
+	ID           string                      `json:"id" doc:"Unique identifier of the run"`
+	JobID        string                      `json:"job_id" doc:"Unique identifier of the job"`
+	Status       shared.RunStatus            `json:"status" doc:"Status of the run" enum:"QUEUED,RUNNING,SUCCEEDED,FAILED,TIMED_OUT,SKIPPED,CANCELLED,MISFIRED"`
+	Trigger      shared.RunTrigger           `json:"trigger" doc:"What started the run" enum:"SCHEDULED,MANUAL,BACKFILL"`
+	Attempt      int                         `json:"attempt" doc:"Attempt number of the run"`
+	ScheduledFor *int64                      `json:"scheduled_for,omitempty" doc:"Logical time of the occurrence the run belongs to (Unix timestamp)"`
+	StartedAt    *time.Time                  `json:"started_at,omitempty" doc:"Time the run started"`
+	FinishedAt   *time.Time                  `json:"finished_at,omitempty" doc:"Time the run finished"`
+	DurationMs   *int64                      `json:"duration_ms,omitempty" doc:"Duration of the run in milliseconds"`
+	ErrorMessage *string                     `json:"error_message,omitempty" doc:"Reason the run failed, was skipped or was cancelled"`
+	Decision     *shared.ConcurrencyDecision `json:"concurrency_decision,omitempty" doc:"How the concurrency policy treated the run, only set if it overlapped with another run" enum:"ALLOWED,SKIPPED,REPLACED"`
+	Result       map[string]interface{}      `json:"result,omitempty" doc:"Output reported by the executor"`
+	CreatedAt    time.Time                   `json:"created_at" doc:"Creation time of the run"`
+}
+
+// Huma response wrappers
+
+type GetJobRunByIDResponse struct {
+	Body JobRunDTO
+}
+
+type FilterJobRunsResponse struct {
+	Body struct {
+		Runs       []JobRunDTO `json:"runs" doc:"List of runs, newest first"`
+		NextCursor string      `json:"next_cursor,omitempty" doc:"Cursor for the next page, empty on the last page"`
+	}
+}
@@ -0,0 +1,112 @@
+package scheduler
+
+import (
+	"context"
+	"maps"
+	"slices"
+	"sync"
+
+	"github.com/sdivyansh59/digantara-backend-golang-assignment/app/job"
+	"github.com/sdivyansh59/digantara-backend-golang-assignment/app/jobrun"
+	"github.com/sdivyansh59/digantara-backend-golang-assignment/app/shared"
+	"github.com/sdivyansh59/digantara-backend-golang-assignment/internal-lib/snowflake"
+)
+
+// serialBackfills tracks the jobs whose queued backfill runs this instance runs one after another.
+type serialBackfills struct {
+	mutex sync.Mutex
+	jobs  map[snowflake.ID]struct{}
+}
+
+func newSerialBackfills() *serialBackfills {
+	return &serialBackfills{jobs: make(map[snowflake.ID]struct{})}
+}
+
+// start marks the job as being backfilled. Returns false if it already is.
+func (s *serialBackfills) start(jobID snowflake.ID) bool {
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+
+	if _, ok := s.jobs[jobID]; ok {
+		return false
+	}
+	s.jobs[jobID] = struct{}{}
+
+	return true
+}
+
+func (s *serialBackfills) stop(jobID snowflake.ID) {
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+
+	delete(s.jobs, jobID)
+}
+
+// jobIDs returns the jobs being backfilled.
+func (s *serialBackfills) jobIDs() []snowflake.ID {
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+
+	return slices.Collect(maps.Keys(s.jobs))
+}
+
+// backfillSerially runs the queued backfill runs of a job without the Allow policy one after another, in the order of
+// their occurrences, each once no other run of the job is in flight. So a backfill neither gets skipped by Forbid nor
+// cancels the regular run under Replace. It stops once no run is left, ctx is done or this instance lost its
+// leadership, the remaining runs stay QUEUED for the next leader.
+func (c *Controller) backfillSerially(ctx context.Context, jobToRun *job.Job) {
+	defer func() {
+		c.backfills.stop(jobToRun.Id)
+		// Runs queued while stopping were left to this loop, let the scheduler loop pick them up
+		c.wake()
+	}()
+
+	for c.elector.IsLeader() {
+		run, err := c.runRepository.GetNextQueued(ctx, jobToRun.Id, shared.RunTriggerBackfill)
+		if err != nil {
+			c.Logger.Error().Err(err).Msgf("Failed to get the next backfill run of job %s", jobToRun.Id)
+			return
+		}
+		if run == nil {
+			c.Logger.Info().Msgf("Backfill of job %s finished", jobToRun.Id)
+			return
+		}
+
+		// Every run works on its own copy of the job
+		job := *jobToRun
+		if err := c.runAlone(ctx, &job, run); err != nil {
+			c.Logger.Info().Msgf("Backfill of job %s stopped before run %s", jobToRun.Id, run.Id)
+			return
+		}
+	}
+}
+
+// runAlone runs the queued run once no other run of the job is in flight, blocking until the run finished.
+// Waiting stops once ctx is done, the run then stays QUEUED.
+func (c *Controller) runAlone(ctx context.Context, job *job.Job, run *jobrun.JobRun) error {
+	if err := c.pool.acquire(ctx); err != nil {
+		return err
+	}
+	defer c.pool.release()
+
+	// Runs outlive the dispatch context, Shutdown cancels them once the grace period is over
+	recordCtx := context.WithoutCancel(ctx)
+	runCtx, cancel := context.WithCancelCause(recordCtx)
+	defer cancel(nil)
+
+	if err := c.inFlight.admitAlone(ctx, job.Id, run.Id, cancel); err != nil {
+		return err
+	}
+
+	claimed, err := c.runRepository.ClaimQueued(ctx, run, c.instanceID, c.leaseDuration)
+	if err != nil || !claimed {
+		c.inFlight.remove(job.Id, run.Id)
+		if err != nil {
+			c.Logger.Error().Err(err).Msgf("Failed to claim backfill run %s of job %s", run.Id, job.Id)
+		}
+		return nil
+	}
+
+	c.runAdmitted(recordCtx, runCtx, cancel, job, run)
+	return nil
+}
@@ -0,0 +1,105 @@
+package scheduler
+
+import (
+	"fmt"
+	"os"
+	"time"
+
+	"github.com/sdivyansh59/digantara-backend-golang-assignment/internal-lib/utils"
+)
+
+const (
+	defaultWorkerPoolSize          = 10
+	defaultPriorityAgingSeconds    = 60
+	defaultMisfireThresholdSeconds = 60
+	defaultLeaseSeconds            = 30
+	defaultReaperIntervalSeconds   = 15
+	defaultLeaderLockID            = 7_215_341
+	defaultLeaderPollSeconds       = 5
+	defaultShutdownGraceSeconds    = 30
+)
+
+// Config holds the scheduler settings, read from the environment
+type Config struct {
+	// WorkerPoolSize is the maximum number of runs executing at the same time
+	WorkerPoolSize int
+	// PriorityAging is the time a due job has to wait to gain one priority point
+	PriorityAging time.Duration
+	// MisfireThreshold is how late a job may be picked up before it counts as misfired
+	MisfireThreshold time.Duration
+	// InstanceID identifies this instance as owner of the jobs it runs
+	InstanceID string
+	// LeaseDuration is how long a running job stays leased without a renewal, it is renewed every third of it
+	LeaseDuration time.Duration
+	// ReaperInterval is how often running jobs are checked for expired leases
+	ReaperInterval time.Duration
+	// LeaderElection lets only the instance holding the leader advisory lock dispatch jobs
+	LeaderElection bool
+	// LeaderLockID is the key of the Postgres advisory lock the instances compete for
+	LeaderLockID int64
+	// LeaderPollInterval is how often followers try to take the lock and the leader checks it still holds it
+	LeaderPollInterval time.Duration
+	// ShutdownGracePeriod is how long running executions may take to finish on shutdown before they are cancelled
+	ShutdownGracePeriod time.Duration
+}
+
+// ProvideConfig reads the scheduler configuration from the environment
+func ProvideConfig() *Config {
+	workerPoolSize := utils.GetEnvOrInt64("SCHEDULER_WORKER_POOL_SIZE", defaultWorkerPoolSize)
+	if workerPoolSize < 1 {
+		workerPoolSize = defaultWorkerPoolSize
+	}
+
+	priorityAgingSeconds := utils.GetEnvOrInt64("SCHEDULER_PRIORITY_AGING_SECONDS", defaultPriorityAgingSeconds)
+	if priorityAgingSeconds < 1 {
+		priorityAgingSeconds = defaultPriorityAgingSeconds
+	}
+
+	misfireThresholdSeconds := utils.GetEnvOrInt64("SCHEDULER_MISFIRE_THRESHOLD_SECONDS", defaultMisfireThresholdSeconds)
+	if misfireThresholdSeconds < 0 {
+		misfireThresholdSeconds = defaultMisfireThresholdSeconds
+	}
+
+	leaseSeconds := utils.GetEnvOrInt64("SCHEDULER_LEASE_SECONDS", defaultLeaseSeconds)
+	if leaseSeconds < 3 {
+		leaseSeconds = defaultLeaseSeconds
+	}
+
+	reaperIntervalSeconds := utils.GetEnvOrInt64("SCHEDULER_REAPER_INTERVAL_SECONDS", defaultReaperIntervalSeconds)
+	if reaperIntervalSeconds < 1 {
+		reaperIntervalSeconds = defaultReaperIntervalSeconds
+	}
+
+	leaderPollSeconds := utils.GetEnvOrInt64("SCHEDULER_LEADER_POLL_SECONDS", defaultLeaderPollSeconds)
+	if leaderPollSeconds < 1 {
+		leaderPollSeconds = defaultLeaderPollSeconds
+	}
+
+	shutdownGraceSeconds := utils.GetEnvOrInt64("SCHEDULER_SHUTDOWN_GRACE_PERIOD_SECONDS", defaultShutdownGraceSeconds)
+	if shutdownGraceSeconds < 0 {
+		shutdownGraceSeconds = defaultShutdownGraceSeconds
+	}
+
+	return &Config{
+		WorkerPoolSize:      int(workerPoolSize),
+		PriorityAging:       time.Duration(priorityAgingSeconds) * time.Second,
+		MisfireThreshold:    time.Duration(misfireThresholdSeconds) * time.Second,
+		InstanceID:          utils.GetEnvOr("INSTANCE_ID", defaultInstanceID()),
+		LeaseDuration:       time.Duration(leaseSeconds) * time.Second,
+		ReaperInterval:      time.Duration(reaperIntervalSeconds) * time.Second,
+		LeaderElection:      utils.StringToBoolean(utils.GetEnvOr("SCHEDULER_LEADER_ELECTION", "false")),
+		LeaderLockID:        utils.GetEnvOrInt64("SCHEDULER_LEADER_LOCK_ID", defaultLeaderLockID),
+		LeaderPollInterval:  time.Duration(leaderPollSeconds) * time.Second,
+		ShutdownGracePeriod: time.Duration(shutdownGraceSeconds) * time.Second,
+	}
+}
+
+// defaultInstanceID identifies the instance by host name and process id
+func defaultInstanceID() string {
+	hostname, err := os.Hostname()
+	if err != nil {
+		hostname = "unknown"
+	}
+
+	return fmt.Sprintf("%s-%d", hostname, os.Getpid())
+}
@@ -2,9 +2,12 @@ package scheduler
 
 import (
 	"context"
+	"fmt"
 	"time"
 
+	"github.com/sdivyansh59/digantara-backend-golang-assignment/app/executor"
 	"github.com/sdivyansh59/digantara-backend-golang-assignment/app/job"
+	"github.com/sdivyansh59/digantara-backend-golang-assignment/app/jobrun"
 	"github.com/sdivyansh59/digantara-backend-golang-assignment/app/shared"
 	"github.com/sdivyansh59/digantara-backend-golang-assignment/internal-lib/snowflake"
 	"github.com/sdivyansh59/digantara-backend-golang-assignment/internal-lib/utils"
@@ -12,31 +15,66 @@ import (
 
 const defaultSleepTime = 5 * time.Minute
 
+// emptyClaimBackoff is the least the loop sleeps after a due job could not be claimed, e.g. because another replica
+// holds its row locked, so the loop does not spin while the job keeps being reported as due
+const emptyClaimBackoff = time.Second
+
 type Controller struct {
 	*utils.WithLogger
-	snowflake     *snowflake.Generator
-	jobRepository job.IRepository
-	jobConverter  *job.Converter
-	sleepTime     time.Duration
-	wakeupChan    chan *shared.WakeupEvent // Read-only channel
+	snowflake        *snowflake.Generator
+	jobRepository    job.IRepository
+	runRepository    jobrun.IRepository
+	jobConverter     *job.Converter
+	executors        *executor.Registry
+	elector          Elector
+	listener         WakeupListener
+	inFlight         *inFlightRuns
+	backfills        *serialBackfills
+	pool             *workerPool
+	priorityAging    time.Duration
+	misfireThreshold time.Duration
+	instanceID       string
+	leaseDuration    time.Duration
+	reaperInterval   time.Duration
+	leaderElection   bool
+	shutdownGrace    time.Duration
+	sleepTime        time.Duration
+	wakeups          chan struct{}            // coalesced wakeups, see wake
+	wakeupChan       chan *shared.WakeupEvent // ad-hoc and backfill runs queued through this instance
 }
 
-func NewController(logger *utils.WithLogger, snowflake *snowflake.Generator, repo job.IRepository,
-	converter *job.Converter, wakeupChan chan *shared.WakeupEvent) *Controller {
+func NewController(logger *utils.WithLogger, config *Config, snowflake *snowflake.Generator, repo job.IRepository,
+	runRepository jobrun.IRepository, converter *job.Converter, executors *executor.Registry, elector Elector,
+	listener WakeupListener, wakeupChan chan *shared.WakeupEvent) *Controller {
 	return &Controller{
-		WithLogger:    logger,
-		snowflake:     snowflake,
-		jobRepository: repo,
-		jobConverter:  converter,
-		sleepTime:     1 * time.Minute, // default
-		wakeupChan:    wakeupChan,
+		WithLogger:       logger,
+		snowflake:        snowflake,
+		jobRepository:    repo,
+		runRepository:    runRepository,
+		jobConverter:     converter,
+		executors:        executors,
+		elector:          elector,
+		listener:         listener,
+		inFlight:         newInFlightRuns(),
+		backfills:        newSerialBackfills(),
+		pool:             newWorkerPool(config.WorkerPoolSize),
+		priorityAging:    config.PriorityAging,
+		misfireThreshold: config.MisfireThreshold,
+		instanceID:       config.InstanceID,
+		leaseDuration:    config.LeaseDuration,
+		reaperInterval:   config.ReaperInterval,
+		leaderElection:   config.LeaderElection,
+		shutdownGrace:    config.ShutdownGracePeriod,
+		sleepTime:        1 * time.Minute, // default
+		wakeups:          make(chan struct{}, 1),
+		wakeupChan:       wakeupChan,
 	}
 }
 
 func (c *Controller) findAndUpdateSleepTime(ctx context.Context) {
 	scheduledTime, err := c.jobRepository.GetNextJobScheduledTime(ctx)
 	if err != nil {
-		c.Logger.Error().Err(err)
+		c.Logger.Error().Err(err).Msg("Failed to get next job scheduled time")
 		c.sleepTime = defaultSleepTime
 		return
 	}
@@ -46,81 +84,149 @@ func (c *Controller) findAndUpdateSleepTime(ctx context.Context) {
 		return
 	}
 
-	currentTime := time.Now().UnixMilli()
+	currentTime := time.Now().Unix()
 	if *scheduledTime <= currentTime {
 		c.sleepTime = 0
 		return
 	}
 
-	sleepDuration := time.Duration(*scheduledTime-currentTime) * time.Millisecond
+	sleepDuration := time.Duration(*scheduledTime-currentTime) * time.Second
 	c.sleepTime = sleepDuration
 }
 
-func (c *Controller) runJob(ctx context.Context, job *job.Job) {
-	if job == nil {
-		c.Logger.Info().Msg("No job to run at this time")
-		return
-	}
-
-	// Assuming job will take approx 10sec to execute
-	time.Sleep(10 * time.Second)
-
-	// Note: if it failed then go and marked status = FAILED and handle this case separately.
-
-	// If completed
-	job.Status = shared.JobStatusCompleted
-	job.LastRunAt = utils.ToPointer(time.Now())
-	if job.IntervalTime != nil {
-		nextScheduledTimeInMins := job.IntervalTime
-		// schedule job again for next interval
-		job.ScheduledAt = time.Now().Add(time.Duration(*nextScheduledTimeInMins) * time.Minute).UnixMilli()
-	}
-
-	err := c.jobRepository.Update(ctx, job)
-	if err != nil {
-		c.Logger.Error().Err(err).Msgf("error while updating job status to COMPLETED for job id:%s", job.Id)
-		// update is as a failed job
-		job.Status = shared.JobStatusFailed
-		err = c.jobRepository.Update(ctx, job)
-		if err != nil {
-			c.Logger.Error().Err(err).Msgf("error while updating job status to FAILED for job id:%s", job.Id)
-		}
-
-		return
+// wake makes the scheduler loop re-evaluate when the next job is due (non-blocking).
+// Wakeups are coalesced: while one is pending further ones are dropped, the pending one covers them since the loop
+// reads the current state of the job table once it wakes up.
+func (c *Controller) wake() {
+	select {
+	case c.wakeups <- struct{}{}:
+	default:
 	}
-
-	c.Logger.Info().Msgf("Job with id:%s completed successfully", job.Id)
 }
 
 // Scheduler responsible for running scheduled jobs at their scheduled time.
+// Only the leader dispatches due jobs and queued runs, followers just wait to take over.
+// Dispatching stops once ctx is done, running executions are left to Shutdown.
 func (c *Controller) Scheduler(ctx context.Context) error {
+	go c.listener.Listen(ctx, c.wake)
+	// Jobs may have become due while another instance was leading
+	go c.elector.Campaign(ctx, c.wake)
+
 	go func() {
+		var backoff time.Duration
 		for {
-			c.findAndUpdateSleepTime(ctx)
+			if c.elector.IsLeader() {
+				c.findAndUpdateSleepTime(ctx)
+				c.sleepTime = max(c.sleepTime, backoff)
+			} else {
+				// Woken up once elected
+				c.sleepTime = defaultSleepTime
+			}
 			c.Logger.Info().Msgf("Scheduler sleeping for %v", c.sleepTime)
 
 			timer := time.NewTimer(c.sleepTime)
 
 			select {
+			case <-ctx.Done():
+				timer.Stop()
+				c.Logger.Info().Msg("Scheduler stopped dispatching")
+				return
 			case <-timer.C:
 				// Normal wakeup after sleep
+			case <-c.wakeups:
+				// Early wakeup, a job was scheduled or rescheduled or a run was queued on this or another instance.
+				// Dispatch what is due, the sleep time is re-evaluated afterwards.
+				timer.Stop()
 			case event := <-c.wakeupChan:
-				// Early wakeup triggered by new job
-				c.Logger.Info().Msgf("Scheduler woken up by job %s scheduled at %d", event.JobID, event.ScheduledAt)
+				// Runs were queued through this instance
 				timer.Stop()
+				c.Logger.Info().Msgf("Scheduler woken up for %d queued run(s) of job %s", len(event.RunIDs), event.JobID)
+			}
+
+			if !c.elector.IsLeader() {
+				continue
+			}
 
-				continue // re-evaluate sleep time immediately
+			// Ad-hoc runs were requested to run right away, they go first
+			if err := c.dispatchQueuedRuns(ctx); err != nil {
+				c.Logger.Info().Msg("Scheduler stopped dispatching")
+				return
 			}
 
-			jobToRun, err := c.jobRepository.GetNextJobToRun(ctx)
+			// Only claim a job once a worker is free, due jobs keep waiting as SCHEDULED until then
+			seenAt := time.Now()
+			if err := c.pool.acquire(ctx); err != nil {
+				c.Logger.Info().Msg("Scheduler stopped dispatching")
+				return
+			}
+
+			jobToRun, err := c.jobRepository.GetNextJobToRun(ctx, c.priorityAging, c.instanceID, c.leaseDuration)
 			if err != nil {
 				c.Logger.Error().Err(err).Msg("Failed to get next job to run")
 			}
-
-			go c.runJob(ctx, jobToRun)
+			if jobToRun == nil {
+				c.pool.release()
+				backoff = emptyClaimBackoff
+				continue
+			}
+			backoff = 0
+
+			// Runs outlive the dispatch context, Shutdown cancels them once the grace period is over
+			runCtx := context.WithoutCancel(ctx)
+			go func() {
+				defer c.pool.release()
+				if c.handleMisfire(runCtx, jobToRun, seenAt) {
+					c.runJob(runCtx, jobToRun, c.newScheduledRun(jobToRun))
+				}
+			}()
 		}
 	}()
 
-	c.Logger.Info().Msg("Scheduler started")
+	go c.Reaper(ctx)
+
+	c.Logger.Info().Msgf("Scheduler started as %s", c.instanceID)
 	return nil
 }
+
+func (c *Controller) GetPoolStatus(ctx context.Context, _ *GetPoolStatusInput) (*GetPoolStatusResponse, error) {
+	if !c.isAuthorized(ctx) {
+		return nil, fmt.Errorf("unauthorized: you do not have permission to view the scheduler status")
+	}
+
+	size, busy := c.pool.size(), c.pool.busy()
+
+	return &GetPoolStatusResponse{
+		Body: PoolStatusDTO{
+			Size:        size,
+			Busy:        busy,
+			Waiting:     c.pool.waiting.Load(),
+			Utilisation: float64(busy) / float64(size),
+		},
+	}, nil
+}
+
+func (c *Controller) GetLeader(ctx context.Context, _ *GetLeaderInput) (*GetLeaderResponse, error) {
+	if !c.isAuthorized(ctx) {
+		return nil, fmt.Errorf("unauthorized: you do not have permission to view the scheduler status")
+	}
+
+	leader, err := c.elector.Leader(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("failed to retrieve leader: %w", err)
+	}
+
+	resp := &GetLeaderResponse{
+		Body: LeaderDTO{
+			InstanceID:     c.instanceID,
+			IsLeader:       c.elector.IsLeader(),
+			LeaderElection: c.leaderElection,
+		},
+	}
+	if leader != nil {
+		resp.Body.LeaderID = leader.InstanceID
+		resp.Body.ElectedAt = &leader.ElectedAt
+		resp.Body.RenewedAt = &leader.RenewedAt
+	}
+
+	return resp, nil
+}
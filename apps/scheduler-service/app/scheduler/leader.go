@@ -0,0 +1,221 @@
+package scheduler
+
+import (
+	"context"
+	"database/sql"
+	"database/sql/driver"
+	"errors"
+	"sync/atomic"
+	"time"
+
+	"github.com/sdivyansh59/digantara-backend-golang-assignment/app/setup/dbconfig"
+	"github.com/sdivyansh59/digantara-backend-golang-assignment/internal-lib/utils"
+	"github.com/uptrace/bun"
+)
+
+// resignTimeout bounds how long the leader waits for its lock to be released when it stops
+const resignTimeout = 5 * time.Second
+
+// Elector decides which instance dispatches jobs. Every instance serves HTTP, but only the leader claims due jobs,
+// dispatches queued runs and reaps expired leases.
+type Elector interface {
+	// Campaign competes for leadership until ctx is done, calling elected every time this instance becomes the leader.
+	// Leadership is handed over when ctx is done.
+	Campaign(ctx context.Context, elected func())
+	// IsLeader tells whether this instance leads right now
+	IsLeader() bool
+	// Leader returns the instance announced as leader, nil if none was elected yet
+	Leader(ctx context.Context) (*LeaderRecord, error)
+}
+
+// LeaderRecord announces the instance holding the leader lock, so followers can tell who leads
+type LeaderRecord struct {
+	bun.BaseModel `bun:"table:scheduler_leader,alias:leader"`
+
+	LockID     int64     `bun:"lock_id,pk"`
+	InstanceID string    `bun:"instance_id,notnull"`
+	ElectedAt  time.Time `bun:"elected_at,notnull"`
+	RenewedAt  time.Time `bun:"renewed_at,notnull"` // last time the leader confirmed it still holds the lock
+}
+
+// ProvideElector returns the Postgres advisory lock elector if leader election is enabled. Otherwise every instance
+// is its own leader, as is the single instance keeping its jobs in memory.
+func ProvideElector(logger *utils.WithLogger, config *Config, jobSchedulerDB *dbconfig.JobSchedulerDB, storage dbconfig.Storage) Elector {
+	if config.LeaderElection && storage == dbconfig.StorageMemory {
+		logger.Logger.Warn().Msg("Leader election needs Postgres, ignoring SCHEDULER_LEADER_ELECTION with STORAGE=memory")
+	}
+
+	if !config.LeaderElection || storage == dbconfig.StorageMemory {
+		now := time.Now()
+		return &standaloneElector{leader: LeaderRecord{InstanceID: config.InstanceID, ElectedAt: now, RenewedAt: now}}
+	}
+
+	return &advisoryLockElector{
+		WithLogger:   logger,
+		db:           jobSchedulerDB.DB,
+		lockID:       config.LeaderLockID,
+		instanceID:   config.InstanceID,
+		pollInterval: config.LeaderPollInterval,
+	}
+}
+
+// standaloneElector makes the instance its own leader, used when leader election is disabled.
+type standaloneElector struct {
+	leader LeaderRecord
+}
+
+func (e *standaloneElector) Campaign(_ context.Context, elected func()) {
+	elected()
+}
+
+func (e *standaloneElector) IsLeader() bool {
+	return true
+}
+
+func (e *standaloneElector) Leader(_ context.Context) (*LeaderRecord, error) {
+	return &e.leader, nil
+}
+
+// advisoryLockElector elects the instance holding a session-level Postgres advisory lock.
+// The lock lives as long as the database session that took it, so if the leader dies Postgres releases it and the
+// next follower polling pg_try_advisory_lock takes over.
+type advisoryLockElector struct {
+	*utils.WithLogger
+	db           *bun.DB
+	lockID       int64
+	instanceID   string
+	pollInterval time.Duration
+	leading      atomic.Bool
+	conn         bun.Conn // dedicated session holding the lock while leading, only used by Campaign
+}
+
+func (e *advisoryLockElector) Campaign(ctx context.Context, elected func()) {
+	ticker := time.NewTicker(e.pollInterval)
+	defer ticker.Stop()
+
+	for {
+		if e.leading.Load() {
+			e.renew(ctx)
+		} else if e.tryLead(ctx) {
+			elected()
+		}
+
+		select {
+		case <-ctx.Done():
+			e.resign()
+			return
+		case <-ticker.C:
+		}
+	}
+}
+
+func (e *advisoryLockElector) IsLeader() bool {
+	return e.leading.Load()
+}
+
+func (e *advisoryLockElector) Leader(ctx context.Context) (*LeaderRecord, error) {
+	var leader LeaderRecord
+
+	err := e.db.NewSelect().
+		Model(&leader).
+		Where("lock_id = ?", e.lockID).
+		Scan(ctx)
+	if errors.Is(err, sql.ErrNoRows) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	return &leader, nil
+}
+
+// tryLead takes the lock on a dedicated session and announces this instance as leader.
+func (e *advisoryLockElector) tryLead(ctx context.Context) bool {
+	conn, err := e.db.Conn(ctx)
+	if err != nil {
+		e.Logger.Error().Err(err).Msg("Failed to open session for leader election")
+		return false
+	}
+
+	var acquired bool
+	err = conn.NewRaw("SELECT pg_try_advisory_lock(?)", e.lockID).Scan(ctx, &acquired)
+	if err != nil || !acquired {
+		if err != nil {
+			e.Logger.Error().Err(err).Msg("Failed to try the leader lock")
+		}
+		_ = conn.Close()
+		return false
+	}
+
+	now := time.Now()
+	_, err = conn.NewInsert().
+		Model(&LeaderRecord{LockID: e.lockID, InstanceID: e.instanceID, ElectedAt: now, RenewedAt: now}).
+		On("CONFLICT (lock_id) DO UPDATE").
+		Set("instance_id = EXCLUDED.instance_id").
+		Set("elected_at = EXCLUDED.elected_at").
+		Set("renewed_at = EXCLUDED.renewed_at").
+		Exec(ctx)
+	if err != nil {
+		e.Logger.Error().Err(err).Msg("Failed to announce leadership, releasing the leader lock")
+		discard(conn)
+		return false
+	}
+
+	e.conn = conn
+	e.leading.Store(true)
+	e.Logger.Info().Msgf("Instance %s is now the scheduler leader", e.instanceID)
+
+	return true
+}
+
+// renew confirms the session holding the lock is still alive. If it broke, Postgres released the lock and another
+// instance may lead already, so this one steps down.
+func (e *advisoryLockElector) renew(ctx context.Context) {
+	result, err := e.conn.NewUpdate().
+		Model((*LeaderRecord)(nil)).
+		Set("renewed_at = ?", time.Now()).
+		Where("lock_id = ?", e.lockID).
+		Where("instance_id = ?", e.instanceID).
+		Exec(ctx)
+	if err == nil {
+		if renewed, _ := result.RowsAffected(); renewed > 0 {
+			return
+		}
+	}
+	if ctx.Err() != nil {
+		// Stopping, resign releases the lock
+		return
+	}
+
+	e.Logger.Warn().Err(err).Msgf("Instance %s lost the scheduler leadership", e.instanceID)
+	e.leading.Store(false)
+	discard(e.conn)
+}
+
+// resign releases the lock when the instance stops, so a follower takes over at its next poll instead of waiting
+// for Postgres to notice the session is gone.
+func (e *advisoryLockElector) resign() {
+	if !e.leading.Swap(false) {
+		return
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), resignTimeout)
+	defer cancel()
+
+	_, err := e.conn.NewRaw("SELECT pg_advisory_unlock(?)", e.lockID).Exec(ctx)
+	if err != nil {
+		e.Logger.Warn().Err(err).Msg("Failed to release the leader lock, closing its session")
+		discard(e.conn)
+		return
+	}
+
+	_ = e.conn.Close()
+	e.Logger.Info().Msgf("Instance %s handed over the scheduler leadership", e.instanceID)
+}
+
+// discard closes the session of conn instead of returning it to the pool, which releases any lock it still holds.
+func discard(conn bun.Conn) {
+	_ = conn.Raw(func(any) error { return driver.ErrBadConn })
+	_ = conn.Close()
+}
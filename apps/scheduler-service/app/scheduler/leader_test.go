@@ -0,0 +1,36 @@
+package scheduler
+
+import (
+	"context"
+	"testing"
+
+	"github.com/sdivyansh59/digantara-backend-golang-assignment/app/setup/dbconfig"
+	"github.com/sdivyansh59/digantara-backend-golang-assignment/internal-lib/utils"
+	"github.com/stretchr/testify/require"
+)
+
+func TestProvideElector_Standalone(t *testing.T) {
+	logger := utils.NewTestWithLogger()
+
+	for _, config := range []*Config{
+		{InstanceID: "instance-1"},
+		// Leader election is ignored when the jobs are kept in memory
+		{InstanceID: "instance-1", LeaderElection: true},
+	} {
+		elector := ProvideElector(logger, config, nil, dbconfig.StorageMemory)
+		require.IsType(t, &standaloneElector{}, elector)
+		require.True(t, elector.IsLeader())
+
+		elected := 0
+		elector.Campaign(context.Background(), func() { elected++ })
+		require.Equal(t, 1, elected)
+
+		leader, err := elector.Leader(context.Background())
+		require.NoError(t, err)
+		require.Equal(t, "instance-1", leader.InstanceID)
+	}
+
+	// Without leader election a Postgres instance is its own leader as well
+	elector := ProvideElector(logger, &Config{InstanceID: "instance-2"}, nil, dbconfig.StoragePostgres)
+	require.IsType(t, &standaloneElector{}, elector)
+}
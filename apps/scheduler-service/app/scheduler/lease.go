@@ -0,0 +1,133 @@
+package scheduler
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/sdivyansh59/digantara-backend-golang-assignment/app/job"
+	"github.com/sdivyansh59/digantara-backend-golang-assignment/app/jobrun"
+	"github.com/sdivyansh59/digantara-backend-golang-assignment/app/shared"
+)
+
+// reaperBatchSize bounds the number of expired leases recovered in one pass of the reaper
+const reaperBatchSize = 100
+
+// errLeaseLost is the cancellation cause of runs whose job was reaped because its lease could not be renewed
+var errLeaseLost = errors.New("lease lost")
+
+// keepLease renews the lease of the claimed job every third of the lease duration until ctx is done.
+// Once the lease is lost the job belongs to whoever reaped it, so the run is cancelled.
+func (c *Controller) keepLease(ctx context.Context, job *job.Job, cancel context.CancelCauseFunc) {
+	ticker := time.NewTicker(c.leaseDuration / 3)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+
+		held, err := c.jobRepository.RenewLease(ctx, job, c.leaseDuration)
+		if err != nil {
+			c.Logger.Warn().Err(err).Msgf("error while renewing lease of job id:%s", job.Id)
+			continue
+		}
+		if !held {
+			cancel(fmt.Errorf("%w on job %s", errLeaseLost, job.Id))
+			return
+		}
+	}
+}
+
+// keepRunLease renews the lease of the claimed queued run like keepLease does for jobs.
+// Once the lease is lost the reaper failed the run, so it is cancelled.
+func (c *Controller) keepRunLease(ctx context.Context, run *jobrun.JobRun, cancel context.CancelCauseFunc) {
+	ticker := time.NewTicker(c.leaseDuration / 3)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+
+		held, err := c.runRepository.RenewLease(ctx, run, c.leaseDuration)
+		if err != nil {
+			c.Logger.Warn().Err(err).Msgf("error while renewing lease of run %s of job id:%s", run.Id, run.JobId)
+			continue
+		}
+		if !held {
+			cancel(fmt.Errorf("%w on run %s", errLeaseLost, run.Id))
+			return
+		}
+	}
+}
+
+// releaseLease clears the lease of a job that leaves RUNNING.
+func releaseLease(job *job.Job) {
+	job.Owner = nil
+	job.LeaseExpiresAt = nil
+}
+
+// Reaper periodically recovers jobs and claimed queued runs stuck in RUNNING because the instance executing them died.
+// Only the leader reaps.
+func (c *Controller) Reaper(ctx context.Context) {
+	ticker := time.NewTicker(c.reaperInterval)
+	defer ticker.Stop()
+
+	for {
+		if c.elector.IsLeader() {
+			c.reapExpiredLeases(ctx)
+			c.reapExpiredRunLeases(ctx)
+		}
+
+		select {
+		case <-ctx.Done():
+			c.Logger.Info().Msg("Reaper stopped")
+			return
+		case <-ticker.C:
+		}
+	}
+}
+
+// reapExpiredLeases fails the abandoned run of every job whose lease expired and reschedules or fails the job
+// according to its retry policy, just like a failed execution.
+func (c *Controller) reapExpiredLeases(ctx context.Context) {
+	jobs, err := c.jobRepository.ClaimExpiredLeases(ctx, c.instanceID, c.leaseDuration, reaperBatchSize)
+	if err != nil {
+		c.Logger.Error().Err(err).Msg("Failed to claim jobs with expired leases")
+		return
+	}
+
+	for i := range jobs {
+		expired := &jobs[i]
+		c.Logger.Warn().Msgf("Lease of running job with id:%s expired, recovering attempt %d", expired.Id, expired.Attempt)
+
+		err := c.runRepository.FailRunning(ctx, expired.Id, shared.RunTriggerScheduled,
+			"lease expired, the scheduler instance running the job stopped renewing it")
+		if err != nil {
+			c.Logger.Error().Err(err).Msgf("error while failing abandoned runs of job id:%s", expired.Id)
+		}
+
+		c.retryOrFail(ctx, expired)
+	}
+}
+
+// reapExpiredRunLeases fails the ad-hoc and backfill runs whose lease expired. They are not retried, like any failed
+// run outside of the schedule.
+func (c *Controller) reapExpiredRunLeases(ctx context.Context) {
+	runs, err := c.runRepository.FailExpiredLeases(ctx,
+		"lease expired, the scheduler instance running the run stopped renewing it", reaperBatchSize)
+	if err != nil {
+		c.Logger.Error().Err(err).Msg("Failed to fail runs with expired leases")
+		return
+	}
+
+	for _, run := range runs {
+		c.Logger.Warn().Msgf("Lease of %s run %s of job with id:%s expired, run failed", run.Trigger, run.Id, run.JobId)
+	}
+}
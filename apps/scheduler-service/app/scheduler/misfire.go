@@ -0,0 +1,108 @@
+package scheduler
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/sdivyansh59/digantara-backend-golang-assignment/app/job"
+	"github.com/sdivyansh59/digantara-backend-golang-assignment/app/jobrun"
+	"github.com/sdivyansh59/digantara-backend-golang-assignment/app/shared"
+	"github.com/sdivyansh59/digantara-backend-golang-assignment/internal-lib/utils"
+)
+
+// maxCountedMisfires bounds the number of missed occurrences counted for the audit record
+const maxCountedMisfires = 10000
+
+// handleMisfire applies the job's misfire policy if the claimed job is later than the misfire threshold,
+// e.g. because the scheduler was down at its scheduled time. Every misfire is recorded in the run history.
+// Lateness is measured up to seenAt, when the loop started waiting for a worker to claim a due job, so waiting for a
+// free worker under load does not count as a misfire. Returns whether the claimed occurrence should run now.
+func (c *Controller) handleMisfire(ctx context.Context, job *job.Job, seenAt time.Time) bool {
+	now := time.Now()
+	lateness := seenAt.Sub(c.readyAt(ctx, job))
+	if lateness <= c.misfireThreshold {
+		return true
+	}
+
+	missed, lastMissedAt := job.MissedOccurrences(seenAt, maxCountedMisfires)
+	c.recordMisfire(ctx, job, lateness, missed, lastMissedAt)
+
+	if job.MisfirePolicy != shared.MisfirePolicySkipToNext {
+		// FIRE_ONCE_NOW continues from now after the run, FIRE_ALL_MISSED from the occurrence, see advanceSchedule
+		return true
+	}
+
+	// Skip the missed occurrences, one-time jobs have nothing to skip to and end up in the dead-letter view
+	releaseLease(job)
+	job.Status = shared.JobStatusFailed
+	nextScheduledAt := job.Occurrence()
+	if job.IsRecurring() {
+		next, err := job.NextRunAfter(now)
+		if err != nil {
+			c.Logger.Error().Err(err).Msgf("error while computing next run of job id:%s", job.Id)
+		} else {
+			job.Status = shared.JobStatusScheduled
+			nextScheduledAt = next
+		}
+	}
+	job.StartOccurrence(nextScheduledAt)
+
+	err := c.jobRepository.Reschedule(ctx, job)
+	if err != nil {
+		c.Logger.Error().Err(err).Msgf("error while skipping misfired occurrences of job id:%s", job.Id)
+	}
+
+	return false
+}
+
+// readyAt returns when the job became ready to run: its scheduled time, or the last success of an upstream job
+// that finished after it. Waiting for upstream jobs does not count as being late.
+func (c *Controller) readyAt(ctx context.Context, job *job.Job) time.Time {
+	readyAt := time.Unix(job.ScheduledAt, 0)
+
+	for _, upstreamID := range job.DependsOn {
+		upstream, err := c.jobRepository.GetByID(ctx, upstreamID)
+		if err != nil {
+			c.Logger.Error().Err(err).Msgf("error while loading upstream job %s of job id:%s", upstreamID, job.Id)
+			continue
+		}
+
+		if upstream.LastSucceededAt != nil && upstream.LastSucceededAt.After(readyAt) {
+			readyAt = *upstream.LastSucceededAt
+		}
+	}
+
+	return readyAt
+}
+
+// recordMisfire writes the audit entry of a misfire to the run history.
+func (c *Controller) recordMisfire(ctx context.Context, job *job.Job, lateness time.Duration, missed int, lastMissedAt int64) {
+	lateness = lateness.Truncate(time.Second)
+
+	run := &jobrun.JobRun{
+		Id:           c.snowflake.Next(),
+		JobId:        job.Id,
+		Status:       shared.RunStatusMisfired,
+		Trigger:      shared.RunTriggerScheduled,
+		Attempt:      max(job.Attempt, 1),
+		ScheduledFor: utils.ToPointer(job.Occurrence()),
+		FinishedAt:   utils.ToPointer(time.Now()),
+		ErrorMessage: utils.ToPointer(fmt.Sprintf("misfired by %s (threshold %s), %d occurrence(s) missed, policy %s",
+			lateness, c.misfireThreshold, missed, job.MisfirePolicy)),
+		Result: map[string]interface{}{
+			"policy":             job.MisfirePolicy,
+			"lateness_seconds":   int64(lateness / time.Second),
+			"missed_occurrences": missed,
+			"first_missed_at":    job.Occurrence(),
+			"last_missed_at":     lastMissedAt,
+		},
+	}
+
+	err := c.runRepository.Create(ctx, run)
+	if err != nil {
+		c.Logger.Error().Err(err).Msgf("error while recording misfire of job id:%s", job.Id)
+	}
+
+	c.Logger.Warn().Msgf("Job with id:%s misfired by %s, %d occurrence(s) missed, policy %s", job.Id, lateness, missed, job.MisfirePolicy)
+}
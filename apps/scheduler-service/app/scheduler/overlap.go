@@ -0,0 +1,132 @@
+package scheduler
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sync"
+
+	"github.com/sdivyansh59/digantara-backend-golang-assignment/app/job"
+	"github.com/sdivyansh59/digantara-backend-golang-assignment/app/jobrun"
+	"github.com/sdivyansh59/digantara-backend-golang-assignment/app/shared"
+	"github.com/sdivyansh59/digantara-backend-golang-assignment/internal-lib/snowflake"
+)
+
+// errReplaced is the cancellation cause of runs replaced by a newer run of the same job
+var errReplaced = errors.New("run replaced")
+
+// inFlightRuns tracks the runs executing in this process, per job, so overlapping runs can be detected.
+// Concurrency policies are leader-local: every run is dispatched by the leader, so its in-flight runs are all runs of
+// the cluster, except for runs a previous leader is still finishing after a failover.
+type inFlightRuns struct {
+	mutex sync.Mutex
+	runs  map[snowflake.ID]map[snowflake.ID]context.CancelCauseFunc
+	// idle is closed once the last in-flight run of the job finished
+	idle map[snowflake.ID]chan struct{}
+}
+
+func newInFlightRuns() *inFlightRuns {
+	return &inFlightRuns{
+		runs: make(map[snowflake.ID]map[snowflake.ID]context.CancelCauseFunc),
+		idle: make(map[snowflake.ID]chan struct{}),
+	}
+}
+
+// admit applies the job's concurrency policy to a new run and registers it unless it is skipped.
+// The decision is empty if no other run of the job is in flight. For Forbid and Replace the ids of the runs
+// that were in flight are returned.
+func (f *inFlightRuns) admit(job *job.Job, run *jobrun.JobRun, cancel context.CancelCauseFunc) (shared.ConcurrencyDecision, []snowflake.ID) {
+	f.mutex.Lock()
+	defer f.mutex.Unlock()
+
+	running := f.runs[job.Id]
+	if len(running) == 0 {
+		f.register(job.Id, run.Id, cancel)
+		return "", nil
+	}
+
+	others := make([]snowflake.ID, 0, len(running))
+	for id := range running {
+		others = append(others, id)
+	}
+
+	switch job.ConcurrencyPolicy {
+	case shared.ConcurrencyPolicyForbid:
+		return shared.ConcurrencyDecisionSkipped, others
+	case shared.ConcurrencyPolicyReplace:
+		for id, cancelRun := range running {
+			cancelRun(fmt.Errorf("%w by run %s", errReplaced, run.Id))
+			delete(running, id)
+		}
+		f.register(job.Id, run.Id, cancel)
+		return shared.ConcurrencyDecisionReplaced, others
+	default:
+		f.register(job.Id, run.Id, cancel)
+		return shared.ConcurrencyDecisionAllowed, others
+	}
+}
+
+// admitAlone registers the run once no other run of the job is in flight, blocking until then or until ctx is done.
+// Checking and registering happen under one lock, so no other run is admitted in between.
+func (f *inFlightRuns) admitAlone(ctx context.Context, jobID, runID snowflake.ID, cancel context.CancelCauseFunc) error {
+	for {
+		f.mutex.Lock()
+		if len(f.runs[jobID]) == 0 {
+			f.register(jobID, runID, cancel)
+			f.mutex.Unlock()
+			return nil
+		}
+		idle := f.idle[jobID]
+		f.mutex.Unlock()
+
+		select {
+		case <-idle:
+			// Another waiting run may be admitted first, check again
+		case <-ctx.Done():
+			return ctx.Err()
+		}
+	}
+}
+
+// register adds the run to the in-flight runs of the job. Must be called with mutex held.
+func (f *inFlightRuns) register(jobID, runID snowflake.ID, cancel context.CancelCauseFunc) {
+	running := f.runs[jobID]
+	if running == nil {
+		running = make(map[snowflake.ID]context.CancelCauseFunc)
+		f.runs[jobID] = running
+		f.idle[jobID] = make(chan struct{})
+	}
+
+	running[runID] = cancel
+}
+
+// remove unregisters a finished run.
+func (f *inFlightRuns) remove(jobID, runID snowflake.ID) {
+	f.mutex.Lock()
+	defer f.mutex.Unlock()
+
+	delete(f.runs[jobID], runID)
+	if len(f.runs[jobID]) == 0 {
+		delete(f.runs, jobID)
+		if idle, ok := f.idle[jobID]; ok {
+			close(idle)
+			delete(f.idle, jobID)
+		}
+	}
+}
+
+// cancelAll cancels every run in flight with the given cause and returns how many there were.
+func (f *inFlightRuns) cancelAll(cause error) int {
+	f.mutex.Lock()
+	defer f.mutex.Unlock()
+
+	cancelled := 0
+	for _, running := range f.runs {
+		for _, cancelRun := range running {
+			cancelRun(cause)
+			cancelled++
+		}
+	}
+
+	return cancelled
+}
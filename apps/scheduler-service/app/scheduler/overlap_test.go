@@ -0,0 +1,109 @@
+package scheduler
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/sdivyansh59/digantara-backend-golang-assignment/app/job"
+	"github.com/sdivyansh59/digantara-backend-golang-assignment/app/jobrun"
+	"github.com/sdivyansh59/digantara-backend-golang-assignment/app/shared"
+	"github.com/sdivyansh59/digantara-backend-golang-assignment/internal-lib/snowflake"
+	"github.com/stretchr/testify/require"
+)
+
+func TestInFlightRuns_Admit(t *testing.T) {
+	inFlight := newInFlightRuns()
+	noop := func(error) {}
+
+	allow := &job.Job{Id: 1, ConcurrencyPolicy: shared.ConcurrencyPolicyAllow}
+	decision, others := inFlight.admit(allow, &jobrun.JobRun{Id: 10}, noop)
+	require.Empty(t, decision)
+	require.Empty(t, others)
+
+	decision, others = inFlight.admit(allow, &jobrun.JobRun{Id: 11}, noop)
+	require.Equal(t, shared.ConcurrencyDecisionAllowed, decision)
+	require.Equal(t, []snowflake.ID{10}, others)
+
+	forbid := &job.Job{Id: 2, ConcurrencyPolicy: shared.ConcurrencyPolicyForbid}
+	decision, _ = inFlight.admit(forbid, &jobrun.JobRun{Id: 20}, noop)
+	require.Empty(t, decision)
+
+	decision, others = inFlight.admit(forbid, &jobrun.JobRun{Id: 21}, noop)
+	require.Equal(t, shared.ConcurrencyDecisionSkipped, decision)
+	require.Equal(t, []snowflake.ID{20}, others)
+
+	// Once the running run finished, the next one is admitted again
+	inFlight.remove(forbid.Id, 20)
+	decision, _ = inFlight.admit(forbid, &jobrun.JobRun{Id: 22}, noop)
+	require.Empty(t, decision)
+}
+
+func TestInFlightRuns_Replace(t *testing.T) {
+	inFlight := newInFlightRuns()
+	replace := &job.Job{Id: 3, ConcurrencyPolicy: shared.ConcurrencyPolicyReplace}
+
+	firstCtx, cancelFirst := context.WithCancelCause(context.Background())
+	defer cancelFirst(nil)
+
+	decision, _ := inFlight.admit(replace, &jobrun.JobRun{Id: 30}, cancelFirst)
+	require.Empty(t, decision)
+
+	decision, others := inFlight.admit(replace, &jobrun.JobRun{Id: 31}, func(error) {})
+	require.Equal(t, shared.ConcurrencyDecisionReplaced, decision)
+	require.Equal(t, []snowflake.ID{30}, others)
+	require.True(t, errors.Is(context.Cause(firstCtx), errReplaced))
+
+	// The replaced run is no longer tracked, removing it late must not drop the new run
+	inFlight.remove(replace.Id, 30)
+	decision, _ = inFlight.admit(replace, &jobrun.JobRun{Id: 32}, func(error) {})
+	require.Equal(t, shared.ConcurrencyDecisionReplaced, decision)
+}
+
+func TestInFlightRuns_AdmitAlone(t *testing.T) {
+	inFlight := newInFlightRuns()
+	noop := func(error) {}
+	forbid := &job.Job{Id: 4, ConcurrencyPolicy: shared.ConcurrencyPolicyForbid}
+
+	// Nothing in flight, admitted right away and counted as in flight
+	require.NoError(t, inFlight.admitAlone(context.Background(), forbid.Id, 40, noop))
+	decision, others := inFlight.admit(forbid, &jobrun.JobRun{Id: 41}, noop)
+	require.Equal(t, shared.ConcurrencyDecisionSkipped, decision)
+	require.Equal(t, []snowflake.ID{40}, others)
+
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	require.ErrorIs(t, inFlight.admitAlone(ctx, forbid.Id, 42, noop), context.Canceled)
+
+	// Of two waiting runs only one is admitted once the job is idle
+	done := make(chan error, 2)
+	go func() { done <- inFlight.admitAlone(context.Background(), forbid.Id, 43, noop) }()
+	go func() { done <- inFlight.admitAlone(context.Background(), forbid.Id, 44, noop) }()
+
+	inFlight.remove(forbid.Id, 40)
+	require.NoError(t, <-done)
+	select {
+	case <-done:
+		t.Fatal("both waiting runs were admitted")
+	case <-time.After(50 * time.Millisecond):
+	}
+
+	inFlight.remove(forbid.Id, 43)
+	inFlight.remove(forbid.Id, 44)
+	require.NoError(t, <-done)
+}
+
+func TestInFlightRuns_CancelAll(t *testing.T) {
+	inFlight := newInFlightRuns()
+	allow := &job.Job{Id: 5, ConcurrencyPolicy: shared.ConcurrencyPolicyAllow}
+
+	firstCtx, cancelFirst := context.WithCancelCause(context.Background())
+	secondCtx, cancelSecond := context.WithCancelCause(context.Background())
+	inFlight.admit(allow, &jobrun.JobRun{Id: 50}, cancelFirst)
+	inFlight.admit(allow, &jobrun.JobRun{Id: 51}, cancelSecond)
+
+	require.Equal(t, 2, inFlight.cancelAll(errShutdown))
+	require.ErrorIs(t, context.Cause(firstCtx), errShutdown)
+	require.ErrorIs(t, context.Cause(secondCtx), errShutdown)
+}
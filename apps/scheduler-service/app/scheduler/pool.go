@@ -0,0 +1,63 @@
+package scheduler
+
+import (
+	"context"
+	"sync/atomic"
+	"time"
+)
+
+// drainPollInterval is how often drain checks whether all slots are free again
+const drainPollInterval = 100 * time.Millisecond
+
+// workerPool bounds the number of runs executing at the same time.
+// Work that finds no free slot waits in acquire instead of starting another goroutine.
+type workerPool struct {
+	slots   chan struct{}
+	waiting atomic.Int64
+}
+
+func newWorkerPool(size int) *workerPool {
+	return &workerPool{slots: make(chan struct{}, size)}
+}
+
+// acquire blocks until a slot is free or ctx is done.
+func (p *workerPool) acquire(ctx context.Context) error {
+	p.waiting.Add(1)
+	defer p.waiting.Add(-1)
+
+	select {
+	case p.slots <- struct{}{}:
+		return nil
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+}
+
+// release frees a slot taken by acquire.
+func (p *workerPool) release() {
+	<-p.slots
+}
+
+// drain blocks until no slot is taken anymore or ctx is done.
+func (p *workerPool) drain(ctx context.Context) error {
+	ticker := time.NewTicker(drainPollInterval)
+	defer ticker.Stop()
+
+	for p.busy() > 0 {
+		select {
+		case <-ctx.Done():
+			return ctx.Err()
+		case <-ticker.C:
+		}
+	}
+
+	return nil
+}
+
+func (p *workerPool) size() int {
+	return cap(p.slots)
+}
+
+func (p *workerPool) busy() int {
+	return len(p.slots)
+}
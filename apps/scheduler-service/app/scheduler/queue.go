@@ -0,0 +1,80 @@
+package scheduler
+
+import (
+	"context"
+	"errors"
+
+	"github.com/sdivyansh59/digantara-backend-golang-assignment/app/shared"
+	"github.com/sdivyansh59/digantara-backend-golang-assignment/internal-lib/database"
+)
+
+// queuedBatchSize is how many queued runs the leader dispatches before it looks at the due jobs again
+const queuedBatchSize = 10
+
+// errJobDeleted fails queued runs whose job was deleted before they started
+var errJobDeleted = errors.New("job was deleted before the run started")
+
+// dispatchQueuedRuns starts the ad-hoc and backfill runs queued through the API of any instance, oldest first.
+// The runs are picked up from the run history, so runs queued before an instance stopped are not lost, and only the
+// leader dispatches them, so they share its worker pool and concurrency policies with the scheduled runs.
+// Backfill runs of jobs without the Allow policy are handed to backfillSerially. Returns ctx's error once dispatching
+// stopped.
+func (c *Controller) dispatchQueuedRuns(ctx context.Context) error {
+	runs, err := c.runRepository.GetQueued(ctx, queuedBatchSize, c.backfills.jobIDs())
+	if err != nil {
+		c.Logger.Error().Err(err).Msg("Failed to get queued runs")
+		return nil
+	}
+
+	for i := range runs {
+		run := &runs[i]
+
+		jobToRun, err := c.jobRepository.GetByID(ctx, run.JobId)
+		if errors.Is(err, database.ErrNotFound) {
+			c.finishRun(ctx, run, nil, errJobDeleted)
+			continue
+		}
+		if err != nil {
+			c.Logger.Error().Err(err).Msgf("Failed to load job %s for queued run %s", run.JobId, run.Id)
+			continue
+		}
+
+		if run.Trigger == shared.RunTriggerBackfill && jobToRun.ConcurrencyPolicy != shared.ConcurrencyPolicyAllow {
+			if c.backfills.start(jobToRun.Id) {
+				c.Logger.Info().Msgf("Scheduler starting backfill of job %s", jobToRun.Id)
+				go c.backfillSerially(ctx, jobToRun)
+			}
+			continue
+		}
+
+		if err := c.pool.acquire(ctx); err != nil {
+			return err
+		}
+
+		// Another instance may have claimed the run while it still believed to be the leader
+		claimed, err := c.runRepository.ClaimQueued(ctx, run, c.instanceID, c.leaseDuration)
+		if err != nil || !claimed {
+			c.pool.release()
+			if err != nil {
+				c.Logger.Error().Err(err).Msgf("Failed to claim queued run %s of job %s", run.Id, run.JobId)
+			}
+			continue
+		}
+
+		c.Logger.Info().Msgf("Scheduler dispatching %s run %s for job %s", run.Trigger, run.Id, run.JobId)
+
+		// Runs outlive the dispatch context, Shutdown cancels them once the grace period is over
+		runCtx := context.WithoutCancel(ctx)
+		go func() {
+			defer c.pool.release()
+			c.runJob(runCtx, jobToRun, run)
+		}()
+	}
+
+	if len(runs) == queuedBatchSize {
+		// More runs may be waiting, come back once the due jobs had their turn
+		c.wake()
+	}
+
+	return nil
+}
@@ -0,0 +1,329 @@
+package scheduler
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/sdivyansh59/digantara-backend-golang-assignment/app/executor"
+	"github.com/sdivyansh59/digantara-backend-golang-assignment/app/job"
+	"github.com/sdivyansh59/digantara-backend-golang-assignment/app/jobrun"
+	"github.com/sdivyansh59/digantara-backend-golang-assignment/app/shared"
+	"github.com/sdivyansh59/digantara-backend-golang-assignment/internal-lib/snowflake"
+	"github.com/sdivyansh59/digantara-backend-golang-assignment/internal-lib/utils"
+)
+
+// executorStopGracePeriod is how long a run waits for its executor to return after the run was cancelled
+const executorStopGracePeriod = 10 * time.Second
+
+// errTimedOut marks runs that exceeded the timeout_seconds of their job
+var errTimedOut = errors.New("run timed out")
+
+// newScheduledRun prepares the run history entry for a regular, scheduled execution.
+func (c *Controller) newScheduledRun(job *job.Job) *jobrun.JobRun {
+	if job == nil {
+		return nil
+	}
+
+	return &jobrun.JobRun{
+		Id:           c.snowflake.Next(),
+		JobId:        job.Id,
+		Trigger:      shared.RunTriggerScheduled,
+		Attempt:      max(job.Attempt, 1),
+		ScheduledFor: utils.ToPointer(job.Occurrence()),
+	}
+}
+
+// startRun marks the run as RUNNING in the run history.
+// Scheduled runs are inserted here, ad-hoc and backfill runs already exist, queued or claimed.
+func (c *Controller) startRun(ctx context.Context, run *jobrun.JobRun) error {
+	run.Status = shared.RunStatusRunning
+	run.StartedAt = utils.ToPointer(time.Now())
+
+	if run.CreatedAt.IsZero() {
+		return c.runRepository.Create(ctx, run)
+	}
+
+	return c.runRepository.Update(ctx, run)
+}
+
+// finishRun stores the outcome of the run in the run history.
+func (c *Controller) finishRun(ctx context.Context, run *jobrun.JobRun, result executor.Result, runErr error) {
+	run.Status = shared.RunStatusSucceeded
+	run.FinishedAt = utils.ToPointer(time.Now())
+	run.Result = result
+	if runErr != nil {
+		run.Status = shared.RunStatusFailed
+		run.ErrorMessage = utils.ToPointer(runErr.Error())
+	}
+	if errors.Is(runErr, errTimedOut) {
+		run.Status = shared.RunStatusTimedOut
+	}
+	if errors.Is(runErr, errReplaced) || errors.Is(runErr, errLeaseLost) || errors.Is(runErr, errShutdown) {
+		run.Status = shared.RunStatusCancelled
+	}
+
+	err := c.runRepository.Update(ctx, run)
+	if err != nil {
+		c.Logger.Error().Err(err).Msgf("error while recording run %s of job id:%s as %s", run.Id, run.JobId, run.Status)
+	}
+}
+
+// execute hands the run to the executor registered for the job's type.
+// The executor's context carries the deadline derived from the job's timeout_seconds.
+func (c *Controller) execute(ctx context.Context, job *job.Job, run *jobrun.JobRun) (executor.Result, error) {
+	jobExecutor, err := c.executors.Get(job.Type)
+	if err != nil {
+		return nil, err
+	}
+
+	if job.TimeoutSeconds == nil {
+		return jobExecutor.Execute(ctx, c.newExecutorRequest(job, run))
+	}
+
+	timeout := time.Duration(*job.TimeoutSeconds) * time.Second
+	runCtx, cancel := context.WithTimeout(ctx, timeout)
+	defer cancel()
+
+	type outcome struct {
+		result executor.Result
+		err    error
+	}
+
+	done := make(chan outcome, 1)
+	go func() {
+		result, err := jobExecutor.Execute(runCtx, c.newExecutorRequest(job, run))
+		done <- outcome{result: result, err: err}
+	}()
+
+	var finished outcome
+	select {
+	case finished = <-done:
+	case <-runCtx.Done():
+		// Give the executor a moment to stop cleanly and report what it has, then stop waiting for it
+		select {
+		case finished = <-done:
+		case <-time.After(executorStopGracePeriod):
+			c.Logger.Warn().Msgf("Executor of run %s for job id:%s did not stop after cancellation", run.Id, job.Id)
+			finished = outcome{err: runCtx.Err()}
+		}
+	}
+
+	if errors.Is(runCtx.Err(), context.DeadlineExceeded) {
+		return finished.result, fmt.Errorf("%w after %s: %v", errTimedOut, timeout, finished.err)
+	}
+
+	return finished.result, finished.err
+}
+
+// newExecutorRequest passes the occurrence the run stands for as logical time. Ad-hoc runs have no occurrence,
+// they stand for the time they were triggered.
+func (c *Controller) newExecutorRequest(job *job.Job, run *jobrun.JobRun) *executor.Request {
+	logicalTime := run.CreatedAt
+	if run.ScheduledFor != nil {
+		logicalTime = time.Unix(*run.ScheduledFor, 0)
+	}
+
+	return &executor.Request{
+		JobID:       job.Id,
+		RunID:       run.Id,
+		Attempt:     run.Attempt,
+		LogicalTime: logicalTime,
+		Attributes:  job.Attributes,
+	}
+}
+
+// skipRun records a run that was not started because another run of the job was still in flight.
+func (c *Controller) skipRun(ctx context.Context, job *job.Job, run *jobrun.JobRun, running []snowflake.ID) {
+	run.Status = shared.RunStatusSkipped
+	run.Decision = utils.ToPointer(shared.ConcurrencyDecisionSkipped)
+	run.FinishedAt = utils.ToPointer(time.Now())
+	run.ErrorMessage = utils.ToPointer(fmt.Sprintf("skipped by concurrency policy %s, run(s) %s still running",
+		job.ConcurrencyPolicy, strings.Join(snowflake.ConvertToStrings(running), ", ")))
+
+	var err error
+	if run.CreatedAt.IsZero() {
+		err = c.runRepository.Create(ctx, run)
+	} else {
+		err = c.runRepository.Update(ctx, run)
+	}
+	if err != nil {
+		c.Logger.Error().Err(err).Msgf("error while recording skipped run %s of job id:%s", run.Id, job.Id)
+	}
+
+	c.Logger.Info().Msgf("Run %s of job with id:%s skipped, run(s) %v still running", run.Id, job.Id, running)
+}
+
+func (c *Controller) runJob(ctx context.Context, job *job.Job, run *jobrun.JobRun) {
+	if job == nil {
+		c.Logger.Info().Msg("No job to run at this time")
+		return
+	}
+
+	runCtx, cancel := context.WithCancelCause(ctx)
+	defer cancel(nil)
+
+	decision, others := c.inFlight.admit(job, run, cancel)
+	if decision == shared.ConcurrencyDecisionSkipped {
+		c.skipRun(ctx, job, run, others)
+		if run.Trigger == shared.RunTriggerScheduled {
+			c.advanceSchedule(ctx, job)
+		}
+		return
+	}
+
+	if decision != "" {
+		run.Decision = utils.ToPointer(decision)
+		c.Logger.Info().Msgf("Run %s of job with id:%s overlaps with run(s) %v, policy %s", run.Id, job.Id, others, job.ConcurrencyPolicy)
+	}
+
+	c.runAdmitted(ctx, runCtx, cancel, job, run)
+}
+
+// runAdmitted executes a run admitted to the in-flight runs in runCtx, which cancel cancels, and records its outcome
+// using ctx. The run is unregistered once it finished.
+func (c *Controller) runAdmitted(ctx, runCtx context.Context, cancel context.CancelCauseFunc, job *job.Job, run *jobrun.JobRun) {
+	defer c.inFlight.remove(job.Id, run.Id)
+
+	err := c.startRun(ctx, run)
+	if err != nil {
+		c.Logger.Error().Err(err).Msgf("error while recording start of run %s for job id:%s", run.Id, job.Id)
+	}
+
+	c.Logger.Info().Msgf("Running job with id:%s (run id:%s, type:%s, trigger:%s)", job.Id, run.Id, job.Type, run.Trigger)
+
+	// The claimed job, or the claimed queued run, stays leased to this instance while it runs
+	leaseCtx, stopLease := context.WithCancel(runCtx)
+	if run.Trigger == shared.RunTriggerScheduled {
+		go c.keepLease(leaseCtx, job, cancel)
+	} else if run.Owner != nil {
+		go c.keepRunLease(leaseCtx, run, cancel)
+	}
+
+	result, runErr := c.execute(runCtx, job, run)
+	stopLease()
+	if cause := context.Cause(runCtx); errors.Is(cause, errReplaced) || errors.Is(cause, errLeaseLost) ||
+		errors.Is(cause, errShutdown) {
+		runErr = cause
+	}
+	if runErr != nil {
+		c.Logger.Error().Err(runErr).Msgf("Run %s of job with id:%s failed", run.Id, job.Id)
+	}
+
+	job.LastRunAt = utils.ToPointer(time.Now())
+
+	err = c.jobRepository.RecordRun(ctx, job, runErr == nil)
+	if err != nil {
+		c.Logger.Error().Err(err).Msgf("error while recording %s run %s for job id:%s", run.Trigger, run.Id, job.Id)
+	}
+
+	if errors.Is(runErr, errLeaseLost) && run.Trigger != shared.RunTriggerScheduled {
+		// The reaper already failed the run, recording it again would overwrite that
+		c.Logger.Warn().Msgf("Run %s of job with id:%s lost its lease, leaving the run to the reaper", run.Id, job.Id)
+		return
+	}
+
+	c.finishRun(ctx, run, result, runErr)
+
+	if run.Trigger != shared.RunTriggerScheduled {
+		// Status and scheduled_at belong to the regular schedule
+		c.Logger.Info().Msgf("Out-of-schedule run %s (%s) of job with id:%s finished", run.Id, run.Trigger, job.Id)
+		return
+	}
+
+	if errors.Is(runErr, errLeaseLost) {
+		// The reaper already rescheduled or failed the job
+		c.Logger.Warn().Msgf("Run %s of job with id:%s lost its lease, leaving the job to the reaper", run.Id, job.Id)
+		return
+	}
+
+	if errors.Is(runErr, errShutdown) {
+		c.requeue(ctx, job)
+		return
+	}
+
+	if errors.Is(runErr, errReplaced) {
+		// The replacing run takes over, the occurrence neither failed nor succeeded
+		c.advanceSchedule(ctx, job)
+		return
+	}
+
+	if runErr != nil {
+		c.retryOrFail(ctx, job)
+		return
+	}
+
+	job.LastSucceededAt = job.LastRunAt
+	c.advanceSchedule(ctx, job)
+
+	c.Logger.Info().Msgf("Job with id:%s completed successfully", job.Id)
+}
+
+// advanceSchedule finishes the job's current occurrence. Recurring jobs are scheduled again for their next cron
+// occurrence or interval, one-time jobs are COMPLETED.
+// Jobs firing all missed occurrences continue with the occurrence following the current one, even if it already
+// passed, so they catch up one occurrence after another.
+func (c *Controller) advanceSchedule(ctx context.Context, job *job.Job) {
+	releaseLease(job)
+	job.Status = shared.JobStatusCompleted
+
+	after := time.Now()
+	if job.MisfirePolicy == shared.MisfirePolicyFireAllMissed {
+		after = time.Unix(job.Occurrence(), 0)
+	}
+
+	nextScheduledAt, recurring, err := job.NextScheduledAt(after)
+	if err != nil {
+		c.Logger.Error().Err(err).Msgf("error while computing next run of job id:%s", job.Id)
+	}
+	if recurring {
+		job.Status = shared.JobStatusScheduled
+		job.StartOccurrence(nextScheduledAt)
+	} else {
+		job.StartOccurrence(job.Occurrence())
+	}
+
+	err = c.jobRepository.Reschedule(ctx, job)
+	if err != nil {
+		c.Logger.Error().Err(err).Msgf("error while updating job status to %s for job id:%s", job.Status, job.Id)
+	}
+}
+
+// retryOrFail reschedules the failed occurrence of the job according to its retry policy. Once no attempts are left,
+// or the job has no retry policy, the occurrence has failed, which the run history records. Recurring jobs then
+// continue with their next occurrence, only one-time jobs become FAILED.
+func (c *Controller) retryOrFail(ctx context.Context, job *job.Job) {
+	releaseLease(job)
+	retryAt, retry := job.NextRetryAt(time.Now())
+	if !retry && job.IsRecurring() {
+		c.Logger.Info().Msgf("Occurrence of job with id:%s failed after %d attempt(s), continuing with the next one", job.Id, job.Attempt)
+		c.advanceSchedule(ctx, job)
+		return
+	}
+	if !retry {
+		job.Status = shared.JobStatusFailed
+		err := c.jobRepository.Reschedule(ctx, job)
+		if err != nil {
+			c.Logger.Error().Err(err).Msgf("error while updating job status to FAILED for job id:%s", job.Id)
+		}
+
+		c.Logger.Info().Msgf("Job with id:%s failed after %d attempt(s)", job.Id, job.Attempt)
+		return
+	}
+
+	// The retry keeps the occurrence, so the run history and the catch-up of missed occurrences stay on its schedule
+	job.OccurrenceAt = utils.ToPointer(job.Occurrence())
+	job.Status = shared.JobStatusScheduled
+	job.ScheduledAt = retryAt
+	job.Attempt++
+
+	err := c.jobRepository.Reschedule(ctx, job)
+	if err != nil {
+		c.Logger.Error().Err(err).Msgf("error while scheduling attempt %d of job id:%s", job.Attempt, job.Id)
+		return
+	}
+
+	c.Logger.Info().Msgf("Job with id:%s will retry (attempt %d) at %d", job.Id, job.Attempt, job.ScheduledAt)
+}
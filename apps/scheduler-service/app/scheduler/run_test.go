@@ -0,0 +1,48 @@
+package scheduler
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/sdivyansh59/digantara-backend-golang-assignment/app/executor"
+	"github.com/sdivyansh59/digantara-backend-golang-assignment/app/job"
+	"github.com/sdivyansh59/digantara-backend-golang-assignment/app/jobrun"
+	"github.com/sdivyansh59/digantara-backend-golang-assignment/app/shared"
+	"github.com/sdivyansh59/digantara-backend-golang-assignment/internal-lib/utils"
+	"github.com/stretchr/testify/require"
+)
+
+// blockingExecutor runs until its context is done and reports the partial result it got so far.
+type blockingExecutor struct{}
+
+func (blockingExecutor) Execute(ctx context.Context, _ *executor.Request) (executor.Result, error) {
+	<-ctx.Done()
+	return executor.Result{"partial": true}, ctx.Err()
+}
+
+func TestController_Execute_Timeout(t *testing.T) {
+	registry := executor.NewRegistry()
+	registry.Register("block", blockingExecutor{})
+	controller := &Controller{WithLogger: utils.NewTestWithLogger(), executors: registry}
+
+	jobToRun := &job.Job{Id: 1, Type: "block", TimeoutSeconds: utils.ToPointer(int64(1))}
+	run := &jobrun.JobRun{Id: 10, JobId: 1}
+
+	started := time.Now()
+	result, err := controller.execute(context.Background(), jobToRun, run)
+	require.ErrorIs(t, err, errTimedOut)
+	require.Equal(t, executor.Result{"partial": true}, result)
+	require.Less(t, time.Since(started), 2*time.Second)
+
+	// The run is recorded as TIMED_OUT rather than FAILED
+	runRepository := jobrun.NewMemoryRepository(nil)
+	controller.runRepository = runRepository
+	require.NoError(t, runRepository.Create(context.Background(), run))
+	controller.finishRun(context.Background(), run, result, err)
+
+	stored, err := runRepository.GetByID(context.Background(), run.Id)
+	require.NoError(t, err)
+	require.Equal(t, shared.RunStatusTimedOut, stored.Status)
+	require.Contains(t, *stored.ErrorMessage, "run timed out after 1s")
+}
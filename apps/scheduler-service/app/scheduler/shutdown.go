@@ -0,0 +1,56 @@
+package scheduler
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"github.com/sdivyansh59/digantara-backend-golang-assignment/app/job"
+	"github.com/sdivyansh59/digantara-backend-golang-assignment/app/shared"
+)
+
+// shutdownRecordTimeout is how long cancelled runs get to record their outcome on shutdown
+const shutdownRecordTimeout = 5 * time.Second
+
+// errShutdown is the cancellation cause of runs still executing when the shutdown grace period is over
+var errShutdown = errors.New("scheduler shutting down")
+
+// Shutdown lets the running executions finish within the shutdown grace period, once the context passed to
+// Scheduler is done and no new runs are dispatched. Executions still running afterwards are cancelled, their jobs
+// go back to SCHEDULED so the occurrence runs again.
+func (c *Controller) Shutdown(ctx context.Context) {
+	graceCtx, cancel := context.WithTimeout(ctx, c.shutdownGrace)
+	defer cancel()
+
+	c.Logger.Info().Msgf("Waiting up to %s for %d running execution(s)", c.shutdownGrace, c.pool.busy())
+	if c.pool.drain(graceCtx) == nil {
+		c.Logger.Info().Msg("All executions finished")
+		return
+	}
+
+	cancelled := c.inFlight.cancelAll(errShutdown)
+	c.Logger.Warn().Msgf("Cancelled %d execution(s) still running after %s", cancelled, c.shutdownGrace)
+
+	// Give the cancelled runs the same time to stop as replaced or timed out ones, and a moment to record it
+	stopCtx, cancelStop := context.WithTimeout(ctx, executorStopGracePeriod+shutdownRecordTimeout)
+	defer cancelStop()
+
+	if err := c.pool.drain(stopCtx); err != nil {
+		c.Logger.Error().Msgf("%d execution(s) did not stop, their leases expire and the reaper recovers them", c.pool.busy())
+	}
+}
+
+// requeue puts a job whose run was cancelled by a shutdown back to SCHEDULED. The scheduled time and attempt are kept,
+// so the interrupted occurrence runs again as soon as a scheduler picks it up.
+func (c *Controller) requeue(ctx context.Context, job *job.Job) {
+	releaseLease(job)
+	job.Status = shared.JobStatusScheduled
+
+	err := c.jobRepository.Reschedule(ctx, job)
+	if err != nil {
+		c.Logger.Error().Err(err).Msgf("error while putting job id:%s back to SCHEDULED", job.Id)
+		return
+	}
+
+	c.Logger.Info().Msgf("Job with id:%s put back to SCHEDULED after its run was cancelled", job.Id)
+}
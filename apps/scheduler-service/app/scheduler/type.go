@@ -0,0 +1,33 @@
+package scheduler
+
+import "time"
+
+type GetPoolStatusInput struct{}
+
+type PoolStatusDTO struct {
+	Size        int     `json:"size" doc:"Maximum number of runs executing at the same time"`
+	Busy        int     `json:"busy" doc:"Number of runs executing right now"`
+	Waiting     int64   `json:"waiting" doc:"Number of runs waiting for a free slot"`
+	Utilisation float64 `json:"utilisation" doc:"Share of busy slots, between 0 and 1" example:"0.4"`
+}
+
+type GetLeaderInput struct{}
+
+type LeaderDTO struct {
+	LeaderID       string     `json:"leader_id,omitempty" doc:"Instance dispatching jobs, empty if no leader was elected yet"`
+	ElectedAt      *time.Time `json:"elected_at,omitempty" doc:"Time the leader was elected"`
+	RenewedAt      *time.Time `json:"renewed_at,omitempty" doc:"Last time the leader confirmed it still holds the leader lock"`
+	InstanceID     string     `json:"instance_id" doc:"Instance answering this request"`
+	IsLeader       bool       `json:"is_leader" doc:"Whether the instance answering this request is the leader"`
+	LeaderElection bool       `json:"leader_election" doc:"Whether leader election is enabled, without it every instance dispatches jobs"`
+}
+
+// Huma response wrappers
+
+type GetPoolStatusResponse struct {
+	Body PoolStatusDTO
+}
+
+type GetLeaderResponse struct {
+	Body LeaderDTO
+}
@@ -0,0 +1,119 @@
+package scheduler
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/rs/zerolog"
+	"github.com/sdivyansh59/digantara-backend-golang-assignment/app/job"
+	"github.com/sdivyansh59/digantara-backend-golang-assignment/app/setup/dbconfig"
+	"github.com/sdivyansh59/digantara-backend-golang-assignment/internal-lib/database"
+	"github.com/sdivyansh59/digantara-backend-golang-assignment/internal-lib/utils"
+)
+
+// wakeupChannel is the Postgres notification channel the job and job_run table triggers notify on,
+// see migrations 20241009000014_add_job_wakeup_notify_trigger and 20241009000017_add_job_run_queued_notify_trigger
+const wakeupChannel = "job_wakeup"
+
+// listenRetryDelay is how long the listener waits before reconnecting after its connection failed
+const listenRetryDelay = 5 * time.Second
+
+// WakeupListener tells the scheduler loop that a job was scheduled or rescheduled or a run was queued, on this or any
+// other instance, so the loop re-evaluates what is due.
+type WakeupListener interface {
+	// Listen calls wake for every change until ctx is done, and whenever changes may have gone unnoticed.
+	// wake must not block.
+	Listen(ctx context.Context, wake func())
+}
+
+// ProvideWakeupListener returns the listener for the Postgres notifications of the job table, or for the changes
+// reported by the in-memory job repository.
+func ProvideWakeupListener(logger *utils.WithLogger, log *zerolog.Logger, config *utils.DefaultConfig, storage dbconfig.Storage, jobRepository job.IRepository) (WakeupListener, error) {
+	if storage == dbconfig.StorageMemory {
+		repository, ok := jobRepository.(changeNotifier)
+		if !ok {
+			return nil, fmt.Errorf("job repository %T does not report changes for %s storage", jobRepository, storage)
+		}
+
+		return &memoryListener{changes: repository.Changes()}, nil
+	}
+
+	return &postgresListener{WithLogger: logger, log: log, config: config}, nil
+}
+
+// changeNotifier is implemented by job repositories that report their changes themselves, like job.MemoryRepository.
+type changeNotifier interface {
+	Changes() <-chan struct{}
+}
+
+// postgresListener LISTENs for job_wakeup notifications on a dedicated connection and reconnects if it breaks.
+type postgresListener struct {
+	*utils.WithLogger
+	log    *zerolog.Logger
+	config *utils.DefaultConfig
+}
+
+func (l *postgresListener) Listen(ctx context.Context, wake func()) {
+	for {
+		err := l.listen(ctx, wake)
+		if ctx.Err() != nil {
+			return
+		}
+
+		l.Logger.Warn().Err(err).Msgf("Lost the %s listener connection, reconnecting in %s", wakeupChannel, listenRetryDelay)
+
+		select {
+		case <-ctx.Done():
+			return
+		case <-time.After(listenRetryDelay):
+		}
+	}
+}
+
+// listen holds the connection until it fails or ctx is done.
+func (l *postgresListener) listen(ctx context.Context, wake func()) error {
+	conn, err := database.NewPostgresConn(ctx, l.config, l.log)
+	if err != nil {
+		return err
+	}
+	defer conn.Close(context.Background())
+
+	_, err = conn.Exec(ctx, "LISTEN "+wakeupChannel)
+	if err != nil {
+		return err
+	}
+	l.Logger.Info().Msgf("Scheduler listening on %s", wakeupChannel)
+
+	// Nothing was notified while the connection was down
+	wake()
+
+	for {
+		notification, err := conn.WaitForNotification(ctx)
+		if err != nil {
+			return err
+		}
+
+		l.Logger.Debug().Msgf("Scheduler notified of job change %s", notification.Payload)
+		wake()
+	}
+}
+
+// memoryListener wakes the scheduler on the changes of the in-memory job repository, which takes the place of the
+// Postgres trigger.
+type memoryListener struct {
+	changes <-chan struct{}
+}
+
+func (l *memoryListener) Listen(ctx context.Context, wake func()) {
+	wake()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-l.changes:
+			wake()
+		}
+	}
+}
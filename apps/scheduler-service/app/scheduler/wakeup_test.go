@@ -0,0 +1,53 @@
+package scheduler
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/sdivyansh59/digantara-backend-golang-assignment/app/job"
+	"github.com/sdivyansh59/digantara-backend-golang-assignment/app/setup/dbconfig"
+	"github.com/sdivyansh59/digantara-backend-golang-assignment/app/shared"
+	"github.com/sdivyansh59/digantara-backend-golang-assignment/internal-lib/snowflake"
+	"github.com/sdivyansh59/digantara-backend-golang-assignment/internal-lib/utils"
+	"github.com/stretchr/testify/require"
+)
+
+func TestMemoryListener_WakesOnJobChanges(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	generator, err := snowflake.NewGenerator(1)
+	require.NoError(t, err)
+	repository := job.NewMemoryRepository(generator)
+
+	listener, err := ProvideWakeupListener(utils.NewTestWithLogger(), nil, nil, dbconfig.StorageMemory, repository)
+	require.NoError(t, err)
+
+	wakeups := make(chan struct{}, 10)
+	go listener.Listen(ctx, func() { wakeups <- struct{}{} })
+
+	// The listener wakes once right away, nothing may have been noticed before it started
+	requireWakeup(t, wakeups)
+
+	scheduled := &job.Job{Name: "job", Type: "noop", CreatedBy: "a@b.c", Status: shared.JobStatusScheduled, ScheduledAt: time.Now().Unix()}
+	require.NoError(t, repository.Create(ctx, scheduled))
+	requireWakeup(t, wakeups)
+
+	// Jobs that do not become due earlier do not wake the scheduler
+	paused := &job.Job{Name: "paused", Type: "noop", CreatedBy: "a@b.c", Status: shared.JobStatusPaused, ScheduledAt: time.Now().Unix()}
+	require.NoError(t, repository.Create(ctx, paused))
+	select {
+	case <-wakeups:
+		t.Fatal("woken up by a paused job")
+	case <-time.After(50 * time.Millisecond):
+	}
+}
+
+func requireWakeup(t *testing.T, wakeups <-chan struct{}) {
+	select {
+	case <-wakeups:
+	case <-time.After(time.Second):
+		t.Fatal("not woken up")
+	}
+}
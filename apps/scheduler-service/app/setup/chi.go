@@ -29,7 +29,7 @@ func ProvideSingletonChiRouter() *chi.Mux {
 
 		// CORS middleware for browser clients
 		routerInstance.Use(middleware.SetHeader("Access-Control-Allow-Origin", "*"))
-		routerInstance.Use(middleware.SetHeader("Access-Control-Allow-Methods", "GET, POST, PUT, DELETE, OPTIONS"))
+		routerInstance.Use(middleware.SetHeader("Access-Control-Allow-Methods", "GET, POST, PUT, PATCH, DELETE, OPTIONS"))
 		routerInstance.Use(middleware.SetHeader("Access-Control-Allow-Headers", "Content-Type, Authorization"))
 
 		// Additional useful middlewares
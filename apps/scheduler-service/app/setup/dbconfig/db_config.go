@@ -15,7 +15,37 @@ type JobSchedulerDB struct {
 	*bun.DB
 }
 
-func ProvideJobSchedulerDB(logger *zerolog.Logger, withLogger *utils.WithLogger, config *utils.DefaultConfig) (*JobSchedulerDB, error) {
+// Storage selects where jobs and their runs are kept
+type Storage string
+
+const (
+	// StoragePostgres keeps jobs and runs in the Postgres database (default)
+	StoragePostgres Storage = "postgres"
+	// StorageMemory keeps jobs and runs in memory, to run the service locally or in tests without a database.
+	// Everything is lost when the process stops.
+	StorageMemory Storage = "memory"
+)
+
+// ProvideStorage reads the STORAGE setting
+func ProvideStorage() (Storage, error) {
+	storage := Storage(utils.GetEnvOr("STORAGE", string(StoragePostgres)))
+
+	switch storage {
+	case StoragePostgres, StorageMemory:
+		return storage, nil
+	default:
+		return "", fmt.Errorf("unknown STORAGE %q, expected %q or %q", storage, StoragePostgres, StorageMemory)
+	}
+}
+
+// ProvideJobSchedulerDB connects to Postgres and migrates it. With in-memory storage no connection is opened and DB
+// is nil.
+func ProvideJobSchedulerDB(logger *zerolog.Logger, withLogger *utils.WithLogger, config *utils.DefaultConfig, storage Storage) (*JobSchedulerDB, error) {
+	if storage == StorageMemory {
+		logger.Warn().Msg("STORAGE=memory, jobs are kept in memory and lost when the service stops")
+		return &JobSchedulerDB{}, nil
+	}
+
 	// Use the config as-is without changing ServicePrefix
 	// This allows POSTGRES_DB_URL to be found correctly
 	db := database.NewPostgres(config, logger)
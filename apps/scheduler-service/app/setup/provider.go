@@ -7,6 +7,7 @@ import (
 	"github.com/danielgtaylor/huma/v2/adapters/humachi"
 	"github.com/go-chi/chi/v5"
 	"github.com/sdivyansh59/digantara-backend-golang-assignment/app/job"
+	"github.com/sdivyansh59/digantara-backend-golang-assignment/app/jobrun"
 	"github.com/sdivyansh59/digantara-backend-golang-assignment/app/scheduler"
 	"github.com/sdivyansh59/digantara-backend-golang-assignment/app/shared"
 	"github.com/sdivyansh59/digantara-backend-golang-assignment/internal-lib/snowflake"
@@ -27,15 +28,16 @@ func ProvideSingletonHuma(router *chi.Mux) *huma.API {
 	return humaInstance
 }
 
-// ProvideWakeupChannel creates a buffered channel for scheduler wakeup events
+// ProvideWakeupChannel creates a buffered channel for the ad-hoc and backfill runs queued through the API
 func ProvideWakeupChannel() chan *shared.WakeupEvent {
-	// Buffered channel with capacity of 10 to avoid blocking job creation
+	// Buffered channel with capacity of 10, events beyond are dropped since the runs are queued in the database
 	return make(chan *shared.WakeupEvent, 10)
 }
 
 // Controllers holds all application controllers
 type Controllers struct {
 	Job       *job.Controller
+	JobRun    *jobrun.Controller
 	Scheduler *scheduler.Controller
 	// Add other controllers here as you build them
 }
@@ -43,11 +45,13 @@ type Controllers struct {
 // ProvideControllers wires up all controllers
 func ProvideControllers(
 	jobController *job.Controller,
+	jobRunController *jobrun.Controller,
 	schedulerController *scheduler.Controller,
 	// Add other controllers here as parameters
 ) *Controllers {
 	return &Controllers{
 		Job:       jobController,
+		JobRun:    jobRunController,
 		Scheduler: schedulerController,
 		// Add other controllers
 	}
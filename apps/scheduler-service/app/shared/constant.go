@@ -10,10 +10,79 @@ const (
 	JobStatusRunning   JobStatus = "RUNNING"
 	JobStatusCompleted JobStatus = "COMPLETED"
 	JobStatusFailed    JobStatus = "FAILED"
+	JobStatusPaused    JobStatus = "PAUSED"
 )
 
-// WakeupEvent represents an event to wake up the scheduler
+// RunStatus represents the possible states of a single job execution
+type RunStatus string
+
+const (
+	RunStatusQueued    RunStatus = "QUEUED"
+	RunStatusRunning   RunStatus = "RUNNING"
+	RunStatusSucceeded RunStatus = "SUCCEEDED"
+	RunStatusFailed    RunStatus = "FAILED"
+	RunStatusTimedOut  RunStatus = "TIMED_OUT"
+	RunStatusSkipped   RunStatus = "SKIPPED"
+	RunStatusCancelled RunStatus = "CANCELLED"
+	RunStatusMisfired  RunStatus = "MISFIRED"
+)
+
+// RunTrigger tells what started a job execution
+type RunTrigger string
+
+const (
+	RunTriggerScheduled RunTrigger = "SCHEDULED"
+	RunTriggerManual    RunTrigger = "MANUAL"
+	RunTriggerBackfill  RunTrigger = "BACKFILL"
+)
+
+// ConcurrencyPolicy decides what happens when a run of a job starts while another one is still running,
+// similar to the concurrencyPolicy of a Kubernetes CronJob
+type ConcurrencyPolicy string
+
+const (
+	ConcurrencyPolicyAllow   ConcurrencyPolicy = "Allow"
+	ConcurrencyPolicyForbid  ConcurrencyPolicy = "Forbid"
+	ConcurrencyPolicyReplace ConcurrencyPolicy = "Replace"
+)
+
+// ConcurrencyDecision records how the concurrency policy treated a run that overlapped with another one
+type ConcurrencyDecision string
+
+const (
+	ConcurrencyDecisionAllowed  ConcurrencyDecision = "ALLOWED"
+	ConcurrencyDecisionSkipped  ConcurrencyDecision = "SKIPPED"
+	ConcurrencyDecisionReplaced ConcurrencyDecision = "REPLACED"
+)
+
+// MisfirePolicy decides what happens to a recurring job whose scheduled time passed while the scheduler was down
+type MisfirePolicy string
+
+const (
+	// MisfirePolicyFireOnceNow runs the job once and continues with its next future occurrence
+	MisfirePolicyFireOnceNow MisfirePolicy = "FIRE_ONCE_NOW"
+	// MisfirePolicyFireAllMissed runs every missed occurrence, one after another
+	MisfirePolicyFireAllMissed MisfirePolicy = "FIRE_ALL_MISSED"
+	// MisfirePolicySkipToNext drops the missed occurrences and waits for the next future occurrence
+	MisfirePolicySkipToNext MisfirePolicy = "SKIP_TO_NEXT"
+)
+
+// FailureReason tells why a job ended up in the dead-letter view
+type FailureReason string
+
+const (
+	FailureReasonExecutionFailed  FailureReason = "EXECUTION_FAILED"
+	FailureReasonTimedOut         FailureReason = "TIMED_OUT"
+	FailureReasonRetriesExhausted FailureReason = "RETRIES_EXHAUSTED"
+	FailureReasonMisfired         FailureReason = "MISFIRED"
+)
+
+// WakeupEvent tells the scheduler of the same instance about runs requested through the API.
+// The runs are queued in the run history, where the leader picks them up, the event only wakes the scheduler
+// without waiting for the database notification. Plain wakeups for created or rescheduled jobs are notified by the
+// database, see scheduler.WakeupListener.
 type WakeupEvent struct {
-	JobID       snowflake.ID
-	ScheduledAt int64
+	JobID snowflake.ID
+	// RunIDs are the handles of the queued runs
+	RunIDs []snowflake.ID
 }
@@ -5,7 +5,9 @@ package app
 
 import (
 	"github.com/google/wire"
+	"github.com/sdivyansh59/digantara-backend-golang-assignment/app/executor"
 	"github.com/sdivyansh59/digantara-backend-golang-assignment/app/job"
+	"github.com/sdivyansh59/digantara-backend-golang-assignment/app/jobrun"
 	"github.com/sdivyansh59/digantara-backend-golang-assignment/app/scheduler"
 	"github.com/sdivyansh59/digantara-backend-golang-assignment/app/setup"
 	"github.com/sdivyansh59/digantara-backend-golang-assignment/app/setup/dbconfig"
@@ -22,7 +24,8 @@ func InitializeApp() (*App, error) {
 		utils.InitGlobalLogger,
 		utils.NewWithLogger,
 
-		// Database initialization with migrations
+		// Database initialization with migrations, skipped with STORAGE=memory
+		dbconfig.ProvideStorage,
 		dbconfig.ProvideJobSchedulerDB,
 
 		// Infrastructure
@@ -40,8 +43,17 @@ func InitializeApp() (*App, error) {
 		job.NewController,
 		job.NewConverter,
 		job.NewRepository,
+		// job run history
+		jobrun.NewController,
+		jobrun.NewConverter,
+		jobrun.NewRepository,
 		// scheduler
 		scheduler.NewController,
+		scheduler.ProvideConfig,
+		scheduler.ProvideElector,
+		scheduler.ProvideWakeupListener,
+		// executors, keyed by the job type
+		executor.ProvideRegistry,
 	)
 	return nil, nil
 }
@@ -7,7 +7,9 @@
 package app
 
 import (
+	"github.com/sdivyansh59/digantara-backend-golang-assignment/app/executor"
 	"github.com/sdivyansh59/digantara-backend-golang-assignment/app/job"
+	"github.com/sdivyansh59/digantara-backend-golang-assignment/app/jobrun"
 	"github.com/sdivyansh59/digantara-backend-golang-assignment/app/scheduler"
 	"github.com/sdivyansh59/digantara-backend-golang-assignment/app/setup"
 	"github.com/sdivyansh59/digantara-backend-golang-assignment/app/setup/dbconfig"
@@ -34,15 +36,29 @@ func InitializeApp() (*App, error) {
 		return nil, err
 	}
 	converter := job.NewConverter()
-	jobSchedulerDB, err := dbconfig.ProvideJobSchedulerDB(logger, withLogger, defaultConfig)
+	storage, err := dbconfig.ProvideStorage()
 	if err != nil {
 		return nil, err
 	}
-	iRepository := job.NewRepository(generator, jobSchedulerDB)
+	jobSchedulerDB, err := dbconfig.ProvideJobSchedulerDB(logger, withLogger, defaultConfig, storage)
+	if err != nil {
+		return nil, err
+	}
+	iRepository := job.NewRepository(generator, jobSchedulerDB, storage)
+	jobrunIRepository := jobrun.NewRepository(generator, jobSchedulerDB, storage)
+	registry := executor.ProvideRegistry()
 	v := setup.ProvideWakeupChannel()
-	controller := job.NewController(withLogger, generator, converter, iRepository, v)
-	schedulerController := scheduler.NewController(withLogger, generator, iRepository, converter, v)
-	controllers := setup.ProvideControllers(controller, schedulerController)
+	controller := job.NewController(withLogger, generator, converter, iRepository, jobrunIRepository, registry, v)
+	jobrunConverter := jobrun.NewConverter()
+	jobrunController := jobrun.NewController(withLogger, jobrunConverter, jobrunIRepository)
+	config := scheduler.ProvideConfig()
+	elector := scheduler.ProvideElector(withLogger, config, jobSchedulerDB, storage)
+	wakeupListener, err := scheduler.ProvideWakeupListener(withLogger, logger, defaultConfig, storage, iRepository)
+	if err != nil {
+		return nil, err
+	}
+	schedulerController := scheduler.NewController(withLogger, config, generator, iRepository, jobrunIRepository, converter, registry, elector, wakeupListener, v)
+	controllers := setup.ProvideControllers(controller, jobrunController, schedulerController)
 	app := newApp(mux, api, defaultConfig, controllers, withLogger, jobSchedulerDB)
 	return app, nil
 }
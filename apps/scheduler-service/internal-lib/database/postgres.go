@@ -50,6 +50,36 @@ type PostgresConfig struct {
 //
 // See https://inheaden.atlassian.net/l/cp/nW1U21Fq for more information.
 func NewPostgres(config *utils.DefaultConfig, log *zerolog.Logger) *bun.DB {
+	if !postgresDriverRegistered {
+		apmsql.Register("postgres", &stdlib.Driver{})
+		postgresDriverRegistered = true
+	}
+
+	sqlDB, err := apmsql.Open("postgres", getConnectionString(config, log))
+	if err != nil {
+		log.Fatal().Err(err).Msg("could not connect to database")
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), pingTimeout)
+
+	err = sqlDB.PingContext(ctx)
+	if err != nil {
+		log.Fatal().Err(err).Msg("could not connect to database")
+	}
+
+	log.Info().Msg("connected to database")
+	cancel()
+
+	return bun.NewDB(sqlDB, pgdialect.New())
+}
+
+// NewPostgresConn opens a single connection outside of the pool, configured like NewPostgres. It is meant for
+// session-bound features such as LISTEN, the caller has to close it.
+func NewPostgresConn(ctx context.Context, config *utils.DefaultConfig, log *zerolog.Logger) (*pgx.Conn, error) {
+	return pgx.Connect(ctx, getConnectionString(config, log))
+}
+
+func getConnectionString(config *utils.DefaultConfig, log *zerolog.Logger) string {
 	postgresConfig := getPostgresConfig(config, log)
 
 	if postgresConfig.URL != "" {
@@ -70,12 +100,7 @@ func NewPostgres(config *utils.DefaultConfig, log *zerolog.Logger) *bun.DB {
 		postgresConfig.SSLCertLocation = u.Query().Get("sslrootcert")
 	}
 
-	if !postgresDriverRegistered {
-		apmsql.Register("postgres", &stdlib.Driver{})
-		postgresDriverRegistered = true
-	}
-
-	connectionString := fmt.Sprintf("host=%s port=%d user=%s password=%s dbname=%s sslmode=%s sslrootcert=%s",
+	return fmt.Sprintf("host=%s port=%d user=%s password=%s dbname=%s sslmode=%s sslrootcert=%s",
 		postgresConfig.Host,
 		postgresConfig.Port,
 		postgresConfig.Username,
@@ -84,23 +109,6 @@ func NewPostgres(config *utils.DefaultConfig, log *zerolog.Logger) *bun.DB {
 		postgresConfig.SSLMode,
 		postgresConfig.SSLCertLocation,
 	)
-
-	sqlDB, err := apmsql.Open("postgres", connectionString)
-	if err != nil {
-		log.Fatal().Err(err).Msg("could not connect to database")
-	}
-
-	ctx, cancel := context.WithTimeout(context.Background(), pingTimeout)
-
-	err = sqlDB.PingContext(ctx)
-	if err != nil {
-		log.Fatal().Err(err).Msg("could not connect to database")
-	}
-
-	log.Info().Msg("connected to database")
-	cancel()
-
-	return bun.NewDB(sqlDB, pgdialect.New())
 }
 
 func getPostgresConfig(config *utils.DefaultConfig, log *zerolog.Logger) *PostgresConfig {
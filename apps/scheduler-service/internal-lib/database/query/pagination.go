@@ -0,0 +1,72 @@
+package query
+
+import (
+	"encoding/base64"
+	"encoding/json"
+	"fmt"
+
+	"github.com/uptrace/bun"
+)
+
+// Limit caps the number of rows returned by the query.
+func Limit(n int) SearchOption {
+	return func(query *bun.SelectQuery) *bun.SelectQuery {
+		return query.Limit(n)
+	}
+}
+
+// OrderBy sorts by column and uses idColumn as tie-breaker, which keeps the order stable for keyset pagination.
+func OrderBy(column, idColumn string, desc bool) SearchOption {
+	direction := "ASC"
+	if desc {
+		direction = "DESC"
+	}
+
+	return func(query *bun.SelectQuery) *bun.SelectQuery {
+		if column == idColumn {
+			return query.OrderExpr(fmt.Sprintf("%s %s", column, direction))
+		}
+
+		return query.OrderExpr(fmt.Sprintf("%s %s, %s %s", column, direction, idColumn, direction))
+	}
+}
+
+// After only matches rows that sort strictly after the row identified by (value, id) in the order of OrderBy.
+func After[T any, ID any](column string, value T, idColumn string, id ID, desc bool) SearchOption {
+	op := ">"
+	if desc {
+		op = "<"
+	}
+
+	return func(query *bun.SelectQuery) *bun.SelectQuery {
+		if column == idColumn {
+			return query.Where(fmt.Sprintf("%s %s ?", idColumn, op), id)
+		}
+
+		return query.Where(fmt.Sprintf("(%s, %s) %s (?, ?)", column, idColumn, op), value, id)
+	}
+}
+
+// EncodeCursor turns the position of the last returned row into an opaque cursor string.
+func EncodeCursor(position any) (string, error) {
+	encoded, err := json.Marshal(position)
+	if err != nil {
+		return "", fmt.Errorf("failed to encode cursor: %w", err)
+	}
+
+	return base64.RawURLEncoding.EncodeToString(encoded), nil
+}
+
+// DecodeCursor reads a cursor created by EncodeCursor into position.
+func DecodeCursor(cursor string, position any) error {
+	decoded, err := base64.RawURLEncoding.DecodeString(cursor)
+	if err != nil {
+		return fmt.Errorf("malformed cursor: %w", err)
+	}
+
+	if err := json.Unmarshal(decoded, position); err != nil {
+		return fmt.Errorf("malformed cursor: %w", err)
+	}
+
+	return nil
+}
@@ -1,7 +1,9 @@
 package query
 
 import (
+	"encoding/json"
 	"fmt"
+	"strings"
 
 	"github.com/uptrace/bun"
 )
@@ -9,7 +11,55 @@ import (
 type SearchOption func(*bun.SelectQuery) *bun.SelectQuery
 
 func Where[T any](attr string, v T) SearchOption {
+	return WhereOp(attr, "=", v)
+}
+
+// WhereOp compares attr with v using the given SQL operator, e.g. ">=" or "LIKE".
+func WhereOp[T any](attr, op string, v T) SearchOption {
 	return func(query *bun.SelectQuery) *bun.SelectQuery {
-		return query.Where(fmt.Sprintf("%s = ?", attr), v)
+		return query.Where(fmt.Sprintf("%s %s ?", attr, op), v)
 	}
 }
+
+// WhereGte matches rows where attr is greater than or equal to v.
+func WhereGte[T any](attr string, v T) SearchOption {
+	return WhereOp(attr, ">=", v)
+}
+
+// WhereLte matches rows where attr is less than or equal to v.
+func WhereLte[T any](attr string, v T) SearchOption {
+	return WhereOp(attr, "<=", v)
+}
+
+// WhereIn matches rows where attr equals one of the values.
+func WhereIn[T any](attr string, values []T) SearchOption {
+	return func(query *bun.SelectQuery) *bun.SelectQuery {
+		return query.Where(fmt.Sprintf("%s IN (?)", attr), bun.In(values))
+	}
+}
+
+// WhereArrayContains matches rows where the array column attr has v as one of its elements.
+func WhereArrayContains[T any](attr string, v T) SearchOption {
+	return func(query *bun.SelectQuery) *bun.SelectQuery {
+		return query.Where(fmt.Sprintf("? = ANY(%s)", attr), v)
+	}
+}
+
+// WhereHasPrefix matches rows where the text column attr starts with prefix.
+// LIKE wildcards inside prefix are matched literally.
+func WhereHasPrefix(attr, prefix string) SearchOption {
+	escaped := strings.NewReplacer(`\`, `\\`, "%", `\%`, "_", `\_`).Replace(prefix)
+	return WhereOp(attr, "LIKE", escaped+"%")
+}
+
+// WhereJSONContains matches rows where the JSONB column attr contains v (Postgres @> operator).
+func WhereJSONContains(attr string, v any) (SearchOption, error) {
+	encoded, err := json.Marshal(v)
+	if err != nil {
+		return nil, fmt.Errorf("failed to encode json filter for %s: %w", attr, err)
+	}
+
+	return func(query *bun.SelectQuery) *bun.SelectQuery {
+		return query.Where(fmt.Sprintf("%s @> ?::jsonb", attr), string(encoded))
+	}, nil
+}
@@ -0,0 +1,79 @@
+package query
+
+import (
+	"database/sql"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+	"github.com/uptrace/bun"
+	"github.com/uptrace/bun/dialect/pgdialect"
+)
+
+type testModel struct {
+	bun.BaseModel `bun:"table:test"`
+
+	ID int64 `bun:"id,pk"`
+}
+
+func buildQuery(options ...SearchOption) string {
+	db := bun.NewDB(&sql.DB{}, pgdialect.New())
+	q := db.NewSelect().Model((*testModel)(nil))
+
+	for _, option := range options {
+		q = option(q)
+	}
+
+	return q.String()
+}
+
+func TestSearchOptions(t *testing.T) {
+	contains, err := WhereJSONContains("attributes", map[string]interface{}{"department": "engineering"})
+	require.NoError(t, err)
+
+	sql := buildQuery(
+		Where("created_by", "a@b.c"),
+		WhereIn("status", []string{"SCHEDULED", "PAUSED"}),
+		WhereGte("scheduled_at", int64(10)),
+		WhereLte("scheduled_at", int64(20)),
+		WhereHasPrefix("name", "daily_%"),
+		contains,
+	)
+
+	require.Contains(t, sql, `(created_by = 'a@b.c')`)
+	require.Contains(t, sql, `(status IN ('SCHEDULED', 'PAUSED'))`)
+	require.Contains(t, sql, `(scheduled_at >= 10)`)
+	require.Contains(t, sql, `(scheduled_at <= 20)`)
+	require.Contains(t, sql, `(name LIKE 'daily\_\%%')`)
+	require.Contains(t, sql, `(attributes @> '{"department":"engineering"}'::jsonb)`)
+}
+
+func TestPaginationOptions(t *testing.T) {
+	sql := buildQuery(
+		After("scheduled_at", int64(10), "id", int64(42), false),
+		OrderBy("scheduled_at", "id", false),
+		Limit(5),
+	)
+	require.Contains(t, sql, `WHERE ((scheduled_at, id) > (10, 42)) ORDER BY scheduled_at ASC, id ASC LIMIT 5`)
+
+	sql = buildQuery(
+		After("id", int64(42), "id", int64(42), true),
+		OrderBy("id", "id", true),
+	)
+	require.Contains(t, sql, `WHERE (id < 42) ORDER BY id DESC`)
+}
+
+func TestCursorRoundTrip(t *testing.T) {
+	type position struct {
+		Sort string `json:"s"`
+		ID   int64  `json:"id"`
+	}
+
+	cursor, err := EncodeCursor(position{Sort: "id", ID: 42})
+	require.NoError(t, err)
+
+	var decoded position
+	require.NoError(t, DecodeCursor(cursor, &decoded))
+	require.Equal(t, position{Sort: "id", ID: 42}, decoded)
+
+	require.Error(t, DecodeCursor("not a cursor!", &decoded))
+}
@@ -1,6 +1,7 @@
 package utils
 
 import (
+	"encoding/json"
 	"errors"
 	"time"
 
@@ -52,6 +53,16 @@ func SafeDereference[T any](ptr *T, def T) T {
 	return *ptr
 }
 
+// CopyPointer returns a pointer to a copy of the value ptr points to, or nil if ptr is nil.
+func CopyPointer[T any](ptr *T) *T {
+	if ptr == nil {
+		return nil
+	}
+
+	value := *ptr
+	return &value
+}
+
 func BooleanToString(b bool) string {
 	if b {
 		return "true"
@@ -143,3 +154,13 @@ func SafeDereferenceBool(v *bool) bool {
 
 	return *v
 }
+
+// CopyJSON deep-copies src into dst through its JSON encoding, e.g. to copy decoded JSON documents.
+func CopyJSON(src, dst any) error {
+	encoded, err := json.Marshal(src)
+	if err != nil {
+		return err
+	}
+
+	return json.Unmarshal(encoded, dst)
+}
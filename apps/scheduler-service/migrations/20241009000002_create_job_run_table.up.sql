@@ -0,0 +1,19 @@
+-- Create job_run table holding one row per execution of a job
+CREATE TABLE IF NOT EXISTS job_run (
+    id BIGINT PRIMARY KEY,
+    job_id BIGINT NOT NULL REFERENCES job(id) ON DELETE CASCADE,
+    status VARCHAR(20) NOT NULL,
+    trigger_type VARCHAR(20) NOT NULL,
+    attempt INTEGER NOT NULL DEFAULT 1,
+    started_at TIMESTAMP,
+    finished_at TIMESTAMP,
+    error_message TEXT,
+    created_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    updated_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP
+);
+
+-- Create index on job_id for listing the run history of a job (newest first)
+CREATE INDEX IF NOT EXISTS idx_job_run_job_id ON job_run(job_id, id DESC);
+
+-- Create index on status for finding queued and running executions
+CREATE INDEX IF NOT EXISTS idx_job_run_status ON job_run(status);
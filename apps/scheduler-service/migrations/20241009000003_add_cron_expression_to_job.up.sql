@@ -0,0 +1,2 @@
+-- Add cron_expression column for jobs scheduled by a cron expression instead of a fixed interval
+ALTER TABLE job ADD COLUMN IF NOT EXISTS cron_expression VARCHAR(100);
@@ -0,0 +1,2 @@
+-- Add IANA timezone used to evaluate the cron expression or interval of recurring jobs
+ALTER TABLE job ADD COLUMN IF NOT EXISTS timezone VARCHAR(64) NOT NULL DEFAULT 'UTC';
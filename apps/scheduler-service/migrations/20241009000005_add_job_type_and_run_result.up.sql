@@ -0,0 +1,5 @@
+-- Add type column selecting the executor that runs the job
+ALTER TABLE job ADD COLUMN IF NOT EXISTS type VARCHAR(50) NOT NULL DEFAULT 'noop';
+
+-- Add result column holding the output an executor reported for a run
+ALTER TABLE job_run ADD COLUMN IF NOT EXISTS result JSONB;
@@ -0,0 +1,5 @@
+-- Add retry policy for failed scheduled executions, NULL means failed runs are not retried
+ALTER TABLE job ADD COLUMN IF NOT EXISTS retry_policy JSONB;
+
+-- Add attempt column counting the executions of the current occurrence
+ALTER TABLE job ADD COLUMN IF NOT EXISTS attempt INTEGER NOT NULL DEFAULT 1;
@@ -0,0 +1,2 @@
+-- Add timeout_seconds column limiting the duration of a single execution, NULL means no limit
+ALTER TABLE job ADD COLUMN IF NOT EXISTS timeout_seconds BIGINT;
@@ -0,0 +1,8 @@
+-- Add depends_on column listing the upstream jobs that must succeed before the job runs
+ALTER TABLE job ADD COLUMN IF NOT EXISTS depends_on BIGINT[];
+
+-- Add last_succeeded_at column marking the end of the last successful scheduled run
+ALTER TABLE job ADD COLUMN IF NOT EXISTS last_succeeded_at TIMESTAMP;
+
+-- Create index on depends_on for finding the downstream jobs of a job
+CREATE INDEX IF NOT EXISTS idx_job_depends_on ON job USING GIN (depends_on);
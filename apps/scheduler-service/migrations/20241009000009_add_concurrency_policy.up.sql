@@ -0,0 +1,5 @@
+-- Add concurrency_policy column deciding how overlapping runs of a job are treated (Allow, Forbid, Replace)
+ALTER TABLE job ADD COLUMN IF NOT EXISTS concurrency_policy VARCHAR(10) NOT NULL DEFAULT 'Allow';
+
+-- Add concurrency_decision column recording how the policy treated a run that overlapped with another one
+ALTER TABLE job_run ADD COLUMN IF NOT EXISTS concurrency_decision VARCHAR(20);
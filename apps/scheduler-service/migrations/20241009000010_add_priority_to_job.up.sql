@@ -0,0 +1,2 @@
+-- Add priority column, when several jobs are due the ones with a higher priority run first
+ALTER TABLE job ADD COLUMN IF NOT EXISTS priority INTEGER NOT NULL DEFAULT 0;
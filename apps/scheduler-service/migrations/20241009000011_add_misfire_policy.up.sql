@@ -0,0 +1,5 @@
+-- Add misfire_policy column deciding how occurrences missed while the scheduler was down are handled
+ALTER TABLE job ADD COLUMN IF NOT EXISTS misfire_policy VARCHAR(20) NOT NULL DEFAULT 'FIRE_ONCE_NOW';
+
+-- Add scheduled_for column holding the logical time of the occurrence a run belongs to
+ALTER TABLE job_run ADD COLUMN IF NOT EXISTS scheduled_for BIGINT;
@@ -0,0 +1,6 @@
+-- Add lease columns, a RUNNING job is owned by the instance executing it until its lease expires
+ALTER TABLE job ADD COLUMN IF NOT EXISTS owner VARCHAR(255);
+ALTER TABLE job ADD COLUMN IF NOT EXISTS lease_expires_at TIMESTAMP;
+
+-- Create index on lease_expires_at for finding running jobs with an expired lease
+CREATE INDEX IF NOT EXISTS idx_job_lease_expires_at ON job(lease_expires_at) WHERE status = 'RUNNING';
@@ -0,0 +1,7 @@
+-- Create scheduler_leader table recording which instance holds the leader advisory lock
+CREATE TABLE IF NOT EXISTS scheduler_leader (
+    lock_id BIGINT PRIMARY KEY,
+    instance_id VARCHAR(255) NOT NULL,
+    elected_at TIMESTAMP NOT NULL,
+    renewed_at TIMESTAMP NOT NULL
+);
@@ -0,0 +1,24 @@
+-- Notify the schedulers on channel job_wakeup whenever a job may have become due earlier than they expect:
+-- a job was scheduled or rescheduled, or an upstream job succeeded and its dependents may run now
+CREATE OR REPLACE FUNCTION notify_job_wakeup() RETURNS trigger AS $$
+BEGIN
+    IF (TG_OP = 'INSERT' AND NEW.status = 'SCHEDULED')
+        OR (TG_OP = 'UPDATE' AND NEW.status = 'SCHEDULED' AND (
+            OLD.status IS DISTINCT FROM NEW.status
+            OR OLD.scheduled_at IS DISTINCT FROM NEW.scheduled_at
+            OR OLD.depends_on IS DISTINCT FROM NEW.depends_on))
+        OR (TG_OP = 'UPDATE' AND OLD.last_succeeded_at IS DISTINCT FROM NEW.last_succeeded_at) THEN
+        PERFORM pg_notify('job_wakeup', json_build_object(
+            'job_id', NEW.id::text,
+            'scheduled_at', NEW.scheduled_at
+        )::text);
+    END IF;
+
+    RETURN NEW;
+END;
+$$ LANGUAGE plpgsql;
+
+DROP TRIGGER IF EXISTS job_wakeup_notify ON job;
+CREATE TRIGGER job_wakeup_notify
+    AFTER INSERT OR UPDATE ON job
+    FOR EACH ROW EXECUTE FUNCTION notify_job_wakeup();
@@ -0,0 +1,7 @@
+-- scheduled_at used to hold Unix timestamps in milliseconds and now holds seconds.
+-- Convert the rows written before the change. Values of 10^11 and above are milliseconds: as seconds they would lie
+-- past the year 5000, as milliseconds they lie after 1973. Rows already in seconds are left alone, so running the
+-- migration on a converted table changes nothing.
+UPDATE job
+SET scheduled_at = scheduled_at / 1000
+WHERE scheduled_at >= 100000000000;
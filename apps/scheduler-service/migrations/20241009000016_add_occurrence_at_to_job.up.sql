@@ -0,0 +1,3 @@
+-- Add occurrence_at column holding the occurrence a retry belongs to while scheduled_at holds the retry time,
+-- NULL when the job is not retrying and scheduled_at is the occurrence itself
+ALTER TABLE job ADD COLUMN IF NOT EXISTS occurrence_at BIGINT;
@@ -0,0 +1,22 @@
+-- Notify the schedulers on channel job_wakeup whenever a run is queued through the API of any instance,
+-- the leader picks the queued runs up from job_run and dispatches them
+CREATE OR REPLACE FUNCTION notify_job_run_queued() RETURNS trigger AS $$
+BEGIN
+    IF NEW.status = 'QUEUED' THEN
+        PERFORM pg_notify('job_wakeup', json_build_object(
+            'job_id', NEW.job_id::text,
+            'run_id', NEW.id::text
+        )::text);
+    END IF;
+
+    RETURN NEW;
+END;
+$$ LANGUAGE plpgsql;
+
+DROP TRIGGER IF EXISTS job_run_queued_notify ON job_run;
+CREATE TRIGGER job_run_queued_notify
+    AFTER INSERT ON job_run
+    FOR EACH ROW EXECUTE FUNCTION notify_job_run_queued();
+
+-- Create index for the leader finding the queued runs, oldest first
+CREATE INDEX IF NOT EXISTS idx_job_run_queued ON job_run(id) WHERE status = 'QUEUED';
@@ -0,0 +1,4 @@
+-- The reaper also recovers jobs paused during their run once their lease expired,
+-- so the lease index covers PAUSED jobs as well
+DROP INDEX IF EXISTS idx_job_lease_expires_at;
+CREATE INDEX IF NOT EXISTS idx_job_lease_expires_at ON job(lease_expires_at) WHERE status IN ('RUNNING', 'PAUSED');
@@ -0,0 +1,7 @@
+-- Add lease columns, a queued run claimed by an instance is owned by it until its lease expires.
+-- Scheduled runs are covered by the lease of their job and have no lease of their own
+ALTER TABLE job_run ADD COLUMN IF NOT EXISTS owner VARCHAR(255);
+ALTER TABLE job_run ADD COLUMN IF NOT EXISTS lease_expires_at TIMESTAMP;
+
+-- Create index on lease_expires_at for finding running runs with an expired lease
+CREATE INDEX IF NOT EXISTS idx_job_run_lease_expires_at ON job_run(lease_expires_at) WHERE status = 'RUNNING';
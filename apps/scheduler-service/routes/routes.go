@@ -16,7 +16,9 @@ func RegisterRoutes(api *huma.API, c *setup.Controllers) {
 		Path:        "/jobs",
 		Summary:     "Create a new job",
 		Description: "Create a new job with a name, optional description, scheduled time, and creator email. " +
-			"The scheduled time must be a future Unix timestamp.",
+			"The scheduled time must be a future Unix timestamp. Recurring jobs set either interval_time or " +
+			"cron_expression (5 fields, optional seconds field, or macros like @daily and @hourly), " +
+			"evaluated in the IANA timezone of the job.",
 		Tags:          []string{"Jobs"},
 		DefaultStatus: http.StatusCreated,
 	}, c.Job.CreateJob)
@@ -26,10 +28,44 @@ func RegisterRoutes(api *huma.API, c *setup.Controllers) {
 		Method:      http.MethodGet,
 		Path:        "/jobs",
 		Summary:     "Get all jobs",
-		Description: "Retrieve a list of all jobs.",
-		Tags:        []string{"Jobs"},
+		Description: "Retrieve a list of jobs. Filter by status, creator, scheduled time range, name prefix " +
+			"and attribute containment (e.g. attributes={\"department\":\"engineering\"}). " +
+			"Results are paginated, pass next_cursor as cursor to fetch the following page.",
+		Tags: []string{"Jobs"},
 	}, c.Job.FilterJobs)
 
+	huma.Register(*api, huma.Operation{
+		OperationID: "get-dead-letter-jobs",
+		Method:      http.MethodGet,
+		Path:        "/jobs/dead-letter",
+		Summary:     "Get dead-lettered jobs",
+		Description: "Retrieve the jobs that failed permanently, with the failure reason and the error of their " +
+			"last run. Only one-time jobs fail permanently, a recurring job whose occurrence used up its retries " +
+			"continues with its next occurrence and its failed runs are listed in its run history instead. " +
+			"Results are paginated, pass next_cursor as cursor to fetch the following page.",
+		Tags: []string{"Dead Letter"},
+	}, c.Job.FilterDeadLetterJobs)
+
+	huma.Register(*api, huma.Operation{
+		OperationID: "replay-dead-letter-jobs",
+		Method:      http.MethodPost,
+		Path:        "/jobs/dead-letter/replay",
+		Summary:     "Replay dead-lettered jobs",
+		Description: "Reset one or many failed jobs to SCHEDULED at the given time (default: now), " +
+			"with a fresh set of retry attempts.",
+		Tags: []string{"Dead Letter"},
+	}, c.Job.ReplayDeadLetterJobs)
+
+	huma.Register(*api, huma.Operation{
+		OperationID: "get-dependency-graph",
+		Method:      http.MethodGet,
+		Path:        "/jobs/dependency-graph",
+		Summary:     "Get job dependency graph",
+		Description: "Retrieve the jobs that take part in dependencies as nodes and their dependencies as edges " +
+			"from upstream to downstream job. Pass job_id to only get the jobs connected to that job.",
+		Tags: []string{"Jobs"},
+	}, c.Job.GetDependencyGraph)
+
 	huma.Register(*api, huma.Operation{
 		OperationID: "get-job-by-id",
 		Method:      http.MethodGet,
@@ -39,6 +75,59 @@ func RegisterRoutes(api *huma.API, c *setup.Controllers) {
 		Tags:        []string{"Jobs"},
 	}, c.Job.GetJobByID)
 
+	huma.Register(*api, huma.Operation{
+		OperationID: "update-job-by-id",
+		Method:      http.MethodPatch,
+		Path:        "/jobs/{id}",
+		Summary:     "Update job by ID",
+		Description: "Partially update a job. Only the fields present in the body are changed, fields listed in clear " +
+			"are reset to null. Changing scheduled_at reschedules the job and wakes up the scheduler. " +
+			"Responds 409 if the job is running or was claimed by the scheduler while updating it.",
+		Tags: []string{"Jobs"},
+	}, c.Job.UpdateJob)
+
+	huma.Register(*api, huma.Operation{
+		OperationID: "pause-job",
+		Method:      http.MethodPost,
+		Path:        "/jobs/{id}/pause",
+		Summary:     "Pause job",
+		Description: "Pause a scheduled job, or a recurring job while it runs. A paused job is skipped by the scheduler " +
+			"until it is resumed, a running job finishes its current run first.",
+		Tags: []string{"Jobs"},
+	}, c.Job.PauseJob)
+
+	huma.Register(*api, huma.Operation{
+		OperationID: "resume-job",
+		Method:      http.MethodPost,
+		Path:        "/jobs/{id}/resume",
+		Summary:     "Resume job",
+		Description: "Resume a paused job. Recurring jobs continue with their next interval instead of " +
+			"catching up on the runs missed while paused.",
+		Tags: []string{"Jobs"},
+	}, c.Job.ResumeJob)
+
+	huma.Register(*api, huma.Operation{
+		OperationID: "run-job",
+		Method:      http.MethodPost,
+		Path:        "/jobs/{id}/run",
+		Summary:     "Run job now",
+		Description: "Run a job immediately, out of its schedule. The regular scheduled time is not changed. " +
+			"Returns a handle for the ad-hoc run.",
+		Tags:          []string{"Jobs"},
+		DefaultStatus: http.StatusAccepted,
+	}, c.Job.RunJob)
+
+	huma.Register(*api, huma.Operation{
+		OperationID: "backfill-job",
+		Method:      http.MethodPost,
+		Path:        "/jobs/{id}/backfill",
+		Summary:     "Backfill job",
+		Description: "Queue one run of a recurring job for every occurrence between from and to. " +
+			"Each run receives its occurrence as logical execution time. The regular scheduled time is not changed.",
+		Tags:          []string{"Jobs"},
+		DefaultStatus: http.StatusAccepted,
+	}, c.Job.BackfillJob)
+
 	huma.Register(*api, huma.Operation{
 		OperationID: "delete-job-by-id",
 		Method:      http.MethodDelete,
@@ -48,4 +137,43 @@ func RegisterRoutes(api *huma.API, c *setup.Controllers) {
 		Tags:        []string{"Jobs"},
 	}, c.Job.DeleteJobByID)
 
+	// Job run history routes
+	huma.Register(*api, huma.Operation{
+		OperationID: "get-job-runs",
+		Method:      http.MethodGet,
+		Path:        "/jobs/{id}/runs",
+		Summary:     "Get job runs",
+		Description: "Retrieve the run history of a job, newest first. " +
+			"Results are paginated, pass next_cursor as cursor to fetch the following page.",
+		Tags: []string{"Job Runs"},
+	}, c.JobRun.FilterJobRuns)
+
+	huma.Register(*api, huma.Operation{
+		OperationID: "get-job-run-by-id",
+		Method:      http.MethodGet,
+		Path:        "/jobs/{id}/runs/{runId}",
+		Summary:     "Get job run by ID",
+		Description: "Retrieve a single run of a job, including its timing, status and error message.",
+		Tags:        []string{"Job Runs"},
+	}, c.JobRun.GetJobRunByID)
+
+	// Admin routes
+	huma.Register(*api, huma.Operation{
+		OperationID: "get-scheduler-pool",
+		Method:      http.MethodGet,
+		Path:        "/admin/scheduler/pool",
+		Summary:     "Get worker pool status",
+		Description: "Retrieve the size of the scheduler's worker pool, the number of busy slots and of runs " +
+			"waiting for a free slot.",
+		Tags: []string{"Admin"},
+	}, c.Scheduler.GetPoolStatus)
+
+	huma.Register(*api, huma.Operation{
+		OperationID: "get-scheduler-leader",
+		Method:      http.MethodGet,
+		Path:        "/admin/scheduler/leader",
+		Summary:     "Get scheduler leader",
+		Description: "Retrieve the instance currently dispatching jobs and whether the instance answering is the leader.",
+		Tags:        []string{"Admin"},
+	}, c.Scheduler.GetLeader)
 }